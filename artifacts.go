@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// publicURL is the externally reachable base URL of the API, used to build
+// artifact links in webhooks. Links are relative when it is empty.
+var publicURL string
+
+// artifactContentTypes maps artifact file extensions to their content types
+var artifactContentTypes = map[string]string{
+	".mp3":   "audio/mpeg",
+	".wav":   "audio/wav",
+	".m4a":   "audio/mp4",
+	".webm":  "audio/webm",
+	".txt":   "text/plain; charset=utf-8",
+	".jsonl": "application/x-ndjson",
+}
+
+// handleArtifact serves one of a job's files, or an earlier transcript or
+// summary when the "version" query parameter is set. http.ServeContent takes
+// care of Range and conditional requests, so audio can be seeked in a browser.
+func handleArtifact(kind ArtifactKind) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := jobForRequest(w, r)
+		if !ok {
+			return
+		}
+
+		path, ok := job.Artifact(kind)
+		if value := r.URL.Query().Get("version"); value != "" {
+			version, err := strconv.Atoi(value)
+			if err != nil || version < 1 {
+				writeValidationError(w, &ValidationError{Fields: []FieldError{{Field: "version", Message: "must be a positive integer"}}})
+				return
+			}
+			if path, ok = job.ArtifactVersion(kind, version); !ok {
+				writeError(w, http.StatusNotFound, fmt.Sprintf("No %s version %d for this meeting", kind, version))
+				return
+			}
+		}
+		if !ok {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("No %s available for this meeting yet", kind))
+			return
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			writeError(w, http.StatusNotFound, fmt.Sprintf("The %s file is no longer available", kind))
+			return
+		}
+		defer file.Close()
+
+		stat, err := file.Stat()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "Could not read artifact")
+			return
+		}
+
+		if contentType, ok := artifactContentTypes[strings.ToLower(filepath.Ext(path))]; ok {
+			w.Header().Set("Content-Type", contentType)
+		}
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", filepath.Base(path)))
+		http.ServeContent(w, r, filepath.Base(path), stat.ModTime(), file)
+	}
+}
+
+// artifactLinks returns download URLs for the artifacts a job has produced
+func artifactLinks(info JobInfo) map[ArtifactKind]string {
+	if len(info.Artifacts) == 0 {
+		return nil
+	}
+	links := make(map[ArtifactKind]string, len(info.Artifacts))
+	for kind := range info.Artifacts {
+		links[kind] = fmt.Sprintf("%s/meetings/%s/%s", strings.TrimRight(publicURL, "/"), info.ID, kind)
+	}
+	return links
+}
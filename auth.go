@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiKeyPrefix marks tokens issued by keygen so they are easy to spot in logs and secrets scanners
+const apiKeyPrefix = "mk_"
+
+// quotaCheckInterval is how often a recording bot rechecks its key's
+// recorded-minutes quota
+const quotaCheckInterval = 10 * time.Second
+
+var (
+	// ErrQuotaExceeded is returned when an API key has used up one of its limits
+	ErrQuotaExceeded = errors.New("API key quota exceeded")
+	// errRecordingLimit stops a bot whose key has used up its recorded minutes
+	errRecordingLimit = errors.New("recorded-minutes quota used up")
+)
+
+// APIKey is an API client and its limits. Only the SHA-256 hash of the
+// bearer token is stored. A zero limit means unlimited. Admin keys can see
+// every key's meetings.
+type APIKey struct {
+	ID              string `json:"id"`
+	Hash            string `json:"hash"`
+	Admin           bool   `json:"admin,omitempty"`
+	MaxConcurrent   int    `json:"max_concurrent,omitempty"`
+	MeetingsPerDay  int    `json:"meetings_per_day,omitempty"`
+	RecordedMinutes int    `json:"recorded_minutes,omitempty"`
+}
+
+// keysFile is the on-disk format of the API key config
+type keysFile struct {
+	Keys []*APIKey `json:"keys"`
+}
+
+// KeyStore looks up API keys by the hash of their token
+type KeyStore struct {
+	byHash map[string]*APIKey
+}
+
+// apiKeys holds the configured keys; nil disables authentication
+var apiKeys *KeyStore
+
+// quotaMu serializes quota checks with job creation so concurrent requests
+// cannot both slip under a limit
+var quotaMu sync.Mutex
+
+type apiKeyContextKey struct{}
+
+// LoadKeyStore reads API keys from a JSON config file
+func LoadKeyStore(path string) (*KeyStore, error) {
+	file, err := readKeysFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("no API keys configured in %s", path)
+	}
+
+	store := &KeyStore{byHash: make(map[string]*APIKey, len(file.Keys))}
+	for _, key := range file.Keys {
+		if key.ID == "" || key.Hash == "" {
+			return nil, fmt.Errorf("API key entries in %s need an id and a hash", path)
+		}
+		store.byHash[strings.ToLower(key.Hash)] = key
+	}
+	return store, nil
+}
+
+func readKeysFile(path string) (*keysFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file keysFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	return &file, nil
+}
+
+// Authenticate returns the key matching a bearer token
+func (s *KeyStore) Authenticate(token string) (*APIKey, bool) {
+	key, ok := s.byHash[hashToken(token)]
+	return key, ok
+}
+
+// ByID returns the key with the given ID
+func (s *KeyStore) ByID(id string) (*APIKey, bool) {
+	for _, key := range s.byHash {
+		if key.ID == id {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// hashToken returns the hex SHA-256 of a token as stored in the key config
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// publicPaths are served without an API key so that orchestrators can
+// probe and scrape the service
+var publicPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// requireAPIKey rejects requests without a valid bearer token and attaches
+// the caller's key to the request context
+func requireAPIKey(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if apiKeys == nil || publicPaths[r.URL.Path] {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		key, valid := apiKeys.Authenticate(strings.TrimSpace(token))
+		if !ok || !valid {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="meetai"`)
+			writeError(w, http.StatusUnauthorized, "A valid API key is required")
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), apiKeyContextKey{}, key)))
+	})
+}
+
+// apiKeyFrom returns the authenticated key of a request, or nil when
+// authentication is disabled
+func apiKeyFrom(r *http.Request) *APIKey {
+	return apiKeyFromContext(r.Context())
+}
+
+// apiKeyFromContext returns the key an HTTP or gRPC call authenticated with
+func apiKeyFromContext(ctx context.Context) *APIKey {
+	key, _ := ctx.Value(apiKeyContextKey{}).(*APIKey)
+	return key
+}
+
+// apiKeyID returns the ID of the request's key, or "" when authentication is disabled
+func apiKeyID(r *http.Request) string {
+	if key := apiKeyFrom(r); key != nil {
+		return key.ID
+	}
+	return ""
+}
+
+// canAccess reports whether the caller may see a job. Jobs are only visible
+// to the key that launched them, keys that attached to them and admin keys.
+func canAccess(r *http.Request, job *Job) bool {
+	return keyCanAccess(apiKeyFrom(r), job)
+}
+
+func keyCanAccess(key *APIKey, job *Job) bool {
+	return key == nil || key.Admin || job.APIKeyID() == key.ID || job.SubscribedBy(key.ID)
+}
+
+// ownsJob reports whether a key may act on a job as its owner: the key that
+// started it, or an admin key. Keys that only attached to it may not.
+func ownsJob(key *APIKey, job *Job) bool {
+	return key == nil || key.Admin || job.APIKeyID() == key.ID
+}
+
+// checkQuota verifies the key can launch another meeting. Callers must hold quotaMu.
+func checkQuota(key *APIKey, scheduled bool) error {
+	if key == nil {
+		return nil
+	}
+
+	var active, today int
+	startOfDay := time.Now().UTC().Truncate(24 * time.Hour)
+	for _, job := range jobs.List() {
+		info := job.Info()
+		if info.APIKeyID != key.ID {
+			continue
+		}
+		if !info.CreatedAt.Before(startOfDay) {
+			today++
+		}
+		if botActive(info.Status) {
+			active++
+		}
+	}
+
+	if key.MeetingsPerDay > 0 && today >= key.MeetingsPerDay {
+		return fmt.Errorf("%w: %d meetings per day", ErrQuotaExceeded, key.MeetingsPerDay)
+	}
+	if !scheduled && key.MaxConcurrent > 0 && active >= key.MaxConcurrent {
+		return fmt.Errorf("%w: %d concurrent bots", ErrQuotaExceeded, key.MaxConcurrent)
+	}
+	if key.RecordedMinutes > 0 && recordingUsed(key.ID) >= time.Duration(key.RecordedMinutes)*time.Minute {
+		return fmt.Errorf("%w: %d recorded minutes", ErrQuotaExceeded, key.RecordedMinutes)
+	}
+	return nil
+}
+
+// checkRecordingQuota fails a bot about to launch for a key that has no
+// recorded minutes left
+func checkRecordingQuota(job *Job) error {
+	remaining, limited := recordingRemaining(job.APIKeyID())
+	if limited && remaining <= 0 {
+		return fmt.Errorf("%w: recorded minutes used up", ErrQuotaExceeded)
+	}
+	return nil
+}
+
+// enforceRecordingQuota stops a recording bot through stop once the key's
+// bots have recorded its recorded-minutes quota between them. Running bots
+// count what they have recorded so far, so they share what is left and the
+// bot that is recording when it runs out stops.
+func enforceRecordingQuota(ctx context.Context, job *Job, stop context.CancelCauseFunc) {
+	remaining, limited := recordingRemaining(job.APIKeyID())
+	if !limited {
+		return
+	}
+	job.SetRecordingLimit(remaining)
+	for remaining > 0 {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(min(remaining, quotaCheckInterval)):
+		}
+		remaining, _ = recordingRemaining(job.APIKeyID())
+	}
+	stop(errRecordingLimit)
+}
+
+// recordingRemaining returns how much of a key's recorded-minutes quota is
+// left, and false when the key has no such quota
+func recordingRemaining(keyID string) (time.Duration, bool) {
+	quotaMu.Lock()
+	defer quotaMu.Unlock()
+	if apiKeys == nil {
+		return 0, false
+	}
+	key, ok := apiKeys.ByID(keyID)
+	if !ok || key.RecordedMinutes <= 0 {
+		return 0, false
+	}
+	return time.Duration(key.RecordedMinutes)*time.Minute - recordingUsed(key.ID), true
+}
+
+// recordingUsed returns the recording time a key has used up: what its
+// finished bots recorded plus what its bots still in a meeting have
+// recorded so far. Callers must hold quotaMu.
+func recordingUsed(keyID string) time.Duration {
+	var used time.Duration
+	for _, job := range jobs.List() {
+		info := job.Info()
+		if info.APIKeyID != keyID {
+			continue
+		}
+		if started, ok := info.enteredAt(StatusRecording); ok && info.Status == StatusRecording {
+			used += time.Since(started)
+		} else {
+			used += time.Duration(info.RecordedSeconds * float64(time.Second))
+		}
+	}
+	return used
+}
+
+// botActive reports whether a job in this status holds, or is about to hold, a bot
+func botActive(status JobStatus) bool {
+	switch status {
+	case StatusQueued, StatusLaunching, StatusJoining, StatusInLobby, StatusRecording:
+		return true
+	}
+	return false
+}
+
+// runKeygen implements the "keygen" subcommand, which creates a new API key,
+// stores its hash in the key config and prints the token once
+func runKeygen(args []string) error {
+	fs := flag.NewFlagSet("keygen", flag.ExitOnError)
+	keysPath := fs.String("keys-file", "apikeys.json", "API key config file to update")
+	id := fs.String("id", "", "name of the client the key is for")
+	maxConcurrent := fs.Int("max-concurrent", 0, "concurrent bots allowed, 0 for unlimited")
+	perDay := fs.Int("meetings-per-day", 0, "meetings allowed per UTC day, 0 for unlimited")
+	minutes := fs.Int("recorded-minutes", 0, "total recorded minutes allowed, 0 for unlimited")
+	admin := fs.Bool("admin", false, "allow the key to see every key's meetings")
+	fs.Parse(args)
+
+	if *id == "" {
+		return errors.New("-id is required")
+	}
+
+	file, err := readKeysFile(*keysPath)
+	if errors.Is(err, os.ErrNotExist) {
+		file = &keysFile{}
+	} else if err != nil {
+		return err
+	}
+	for _, key := range file.Keys {
+		if key.ID == *id {
+			return fmt.Errorf("a key with id %q already exists", *id)
+		}
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return err
+	}
+	token := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	file.Keys = append(file.Keys, &APIKey{
+		ID:              *id,
+		Hash:            hashToken(token),
+		Admin:           *admin,
+		MaxConcurrent:   *maxConcurrent,
+		MeetingsPerDay:  *perDay,
+		RecordedMinutes: *minutes,
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+	if dir := filepath.Dir(*keysPath); dir != "." {
+		if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(*keysPath, data, 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created API key %q. Store this token now, it cannot be shown again:\n%s\n", *id, token)
+	return nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// recordingFor creates a job for the key that has been recording since ago
+func recordingFor(t *testing.T, keyID string, ago time.Duration) *Job {
+	t.Helper()
+	job := jobs.Create(MeetingRequest{MeetingURL: "https://meet.google.com/abc-defg-hij"}, keyID)
+	t.Cleanup(func() { jobs.Delete(job.ID()) })
+	job.SetStatus(StatusRecording)
+	job.mu.Lock()
+	job.info.History[len(job.info.History)-1].At = time.Now().Add(-ago)
+	job.mu.Unlock()
+	return job
+}
+
+func TestRecordedMinutesAreSharedByRunningBots(t *testing.T) {
+	key := &APIKey{ID: "team", Hash: "hash", RecordedMinutes: 10}
+	saved := apiKeys
+	apiKeys = &KeyStore{byHash: map[string]*APIKey{key.Hash: key}}
+	t.Cleanup(func() { apiKeys = saved })
+
+	finished := jobs.Create(MeetingRequest{MeetingURL: "https://meet.google.com/abc-defg-hij"}, key.ID)
+	t.Cleanup(func() { jobs.Delete(finished.ID()) })
+	finished.SetRecordedDuration(4 * time.Minute)
+	finished.SetStatus(StatusDelivered)
+
+	first := recordingFor(t, key.ID, 3*time.Minute)
+	remaining, limited := recordingRemaining(key.ID)
+	if !limited || remaining < 2*time.Minute || remaining > 3*time.Minute {
+		t.Fatalf("remaining = %v, %v; want about 3m left of the quota", remaining, limited)
+	}
+
+	// A running bot only uses what it has recorded, so others may still start
+	quotaMu.Lock()
+	err := checkQuota(key, false)
+	quotaMu.Unlock()
+	if err != nil {
+		t.Fatalf("checkQuota with minutes left: %v", err)
+	}
+	if err := checkRecordingQuota(first); err != nil {
+		t.Fatalf("checkRecordingQuota with minutes left: %v", err)
+	}
+
+	recordingFor(t, key.ID, 4*time.Minute)
+	quotaMu.Lock()
+	err = checkQuota(key, false)
+	quotaMu.Unlock()
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("checkQuota once the bots recorded the quota = %v, want ErrQuotaExceeded", err)
+	}
+}
@@ -0,0 +1,371 @@
+// Package client is a typed Go client for the meeting bot HTTP API
+// described in openapi.json.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Client talks to a meeting bot server
+type Client struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithAPIKey authenticates every request with a bearer token created by
+// "meeting-bot keygen"
+func WithAPIKey(token string) Option {
+	return func(c *Client) { c.token = token }
+}
+
+// WithHTTPClient replaces the HTTP client used for requests
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) { c.httpClient = httpClient }
+}
+
+// New creates a client for the server at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError is a non-2xx response from the server
+type APIError struct {
+	StatusCode int
+	Message    string       `json:"error"`
+	Fields     []FieldError `json:"fields,omitempty"`
+	// RetryAfter is set when the server asked the client to back off
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	if len(e.Fields) == 0 {
+		return fmt.Sprintf("meeting bot API: %d %s", e.StatusCode, e.Message)
+	}
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return fmt.Sprintf("meeting bot API: %d %s (%s)", e.StatusCode, e.Message, strings.Join(messages, "; "))
+}
+
+// StartMeeting sends a bot into a meeting, or schedules it when StartAt is set
+func (c *Client) StartMeeting(ctx context.Context, req MeetingRequest) (*StartMeetingResponse, error) {
+	return c.StartMeetingIdempotent(ctx, req, "")
+}
+
+// StartMeetingIdempotent is StartMeeting with an Idempotency-Key, so that
+// retrying after a timeout returns the original job instead of sending a
+// second bot
+func (c *Client) StartMeetingIdempotent(ctx context.Context, req MeetingRequest, idempotencyKey string) (*StartMeetingResponse, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	header := http.Header{}
+	if idempotencyKey != "" {
+		header.Set("Idempotency-Key", idempotencyKey)
+	}
+	httpResp, err := c.do(ctx, http.MethodPost, "/start-meeting", nil, bytes.NewReader(data), header)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var resp StartMeetingResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	resp.Replayed = httpResp.Header.Get("Idempotent-Replayed") == "true"
+	return &resp, nil
+}
+
+// UploadRecording sends an existing mp3, wav, m4a or webm recording through
+// the transcribe and summarize pipeline. The file is streamed, not buffered.
+// callbackURL may be empty.
+func (c *Client) UploadRecording(ctx context.Context, filename string, audio io.Reader, callbackURL string) (*StartMeetingResponse, error) {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		err := writeUploadForm(form, filename, audio, callbackURL)
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	header := http.Header{"Content-Type": {form.FormDataContentType()}}
+	httpResp, err := c.do(ctx, http.MethodPost, "/recordings", nil, pr, header)
+	if err != nil {
+		pr.CloseWithError(err)
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	var resp StartMeetingResponse
+	if err := json.NewDecoder(httpResp.Body).Decode(&resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func writeUploadForm(form *multipart.Writer, filename string, audio io.Reader, callbackURL string) error {
+	if callbackURL != "" {
+		if err := form.WriteField("callback_url", callbackURL); err != nil {
+			return err
+		}
+	}
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, audio)
+	return err
+}
+
+// GetMeeting returns the state of a job
+func (c *Client) GetMeeting(ctx context.Context, id string) (*MeetingStatus, error) {
+	var resp MeetingStatus
+	if err := c.doJSON(ctx, http.MethodGet, "/meetings/"+url.PathEscape(id), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListMeetings returns one page of jobs, newest first. Pass the returned
+// NextCursor in opts.Cursor to fetch the next page.
+func (c *Client) ListMeetings(ctx context.Context, opts ListOptions) (*MeetingList, error) {
+	var resp MeetingList
+	if err := c.doJSON(ctx, http.MethodGet, "/meetings", opts.query(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RescheduleMeeting moves a scheduled join to a new start time
+func (c *Client) RescheduleMeeting(ctx context.Context, id string, req RescheduleRequest) (*Job, error) {
+	var resp Job
+	if err := c.doJSON(ctx, http.MethodPatch, "/meetings/"+url.PathEscape(id), nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelMeeting cancels a job or pulls its bot out of the meeting. When
+// discard is true the partial recording is deleted instead of processed. If
+// the caller only attached to the job it is detached and the bot keeps running.
+func (c *Client) CancelMeeting(ctx context.Context, id string, discard bool) (*MeetingStatus, error) {
+	query := url.Values{"recording": {"process"}}
+	if discard {
+		query.Set("recording", "discard")
+	}
+	var resp MeetingStatus
+	if err := c.doJSON(ctx, http.MethodDelete, "/meetings/"+url.PathEscape(id), query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListScheduled returns the joins waiting for their start time, soonest first
+func (c *Client) ListScheduled(ctx context.Context) ([]Job, error) {
+	var resp struct {
+		Meetings []Job `json:"meetings"`
+	}
+	if err := c.doJSON(ctx, http.MethodGet, "/scheduled", nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Meetings, nil
+}
+
+// Reprocess reruns transcription and/or summarization of a finished job.
+// The new outputs become the current transcript and summary; earlier ones
+// stay available through TranscriptVersion and SummaryVersion.
+func (c *Client) Reprocess(ctx context.Context, id string, req ReprocessRequest) (*MeetingStatus, error) {
+	var resp MeetingStatus
+	if err := c.doJSON(ctx, http.MethodPost, "/meetings/"+url.PathEscape(id)+"/reprocess", nil, req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// Recording downloads the meeting audio. The caller must close the reader.
+func (c *Client) Recording(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.artifact(ctx, id, "recording")
+}
+
+// Transcript returns the meeting transcript
+func (c *Client) Transcript(ctx context.Context, id string) (string, error) {
+	return c.artifactText(ctx, id, "transcript", 0)
+}
+
+// Summary returns the meeting summary
+func (c *Client) Summary(ctx context.Context, id string) (string, error) {
+	return c.artifactText(ctx, id, "summary", 0)
+}
+
+// Log downloads the job log as JSON lines, including ffmpeg, Whisper and
+// Ollama output. The caller must close the reader.
+func (c *Client) Log(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.artifact(ctx, id, "log")
+}
+
+// TranscriptVersion returns a specific version of the meeting transcript
+func (c *Client) TranscriptVersion(ctx context.Context, id string, version int) (string, error) {
+	return c.artifactText(ctx, id, "transcript", version)
+}
+
+// SummaryVersion returns a specific version of the meeting summary
+func (c *Client) SummaryVersion(ctx context.Context, id string, version int) (string, error) {
+	return c.artifactText(ctx, id, "summary", version)
+}
+
+// artifactText downloads a text artifact, the current one when version is 0
+func (c *Client) artifactText(ctx context.Context, id, kind string, version int) (string, error) {
+	var query url.Values
+	if version > 0 {
+		query = url.Values{"version": {strconv.Itoa(version)}}
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/meetings/"+url.PathEscape(id)+"/"+kind, query, nil, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return string(data), err
+}
+
+func (c *Client) artifact(ctx context.Context, id, kind string) (io.ReadCloser, error) {
+	resp, err := c.do(ctx, http.MethodGet, "/meetings/"+url.PathEscape(id)+"/"+kind, nil, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Health runs the server's liveness checks. A report with failing checks
+// is returned together with an *APIError carrying status 503.
+func (c *Client) Health(ctx context.Context) (*HealthReport, error) {
+	return c.health(ctx, "/healthz")
+}
+
+// Ready runs the server's readiness checks. A report with failing checks
+// is returned together with an *APIError carrying status 503.
+func (c *Client) Ready(ctx context.Context) (*HealthReport, error) {
+	return c.health(ctx, "/readyz")
+}
+
+// health fetches a health report, which the server also sends with a 503
+func (c *Client) health(ctx context.Context, path string) (*HealthReport, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var report HealthReport
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return nil, &APIError{StatusCode: resp.StatusCode, Message: http.StatusText(resp.StatusCode)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &report, &APIError{StatusCode: resp.StatusCode, Message: "service " + report.Status}
+	}
+	return &report, nil
+}
+
+// doJSON sends a request with an optional JSON body and decodes a JSON response into out
+func (c *Client) doJSON(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		body = bytes.NewReader(data)
+	}
+	resp, err := c.do(ctx, method, path, query, body, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// do sends a request and turns non-2xx responses into an *APIError
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, header http.Header) (*http.Response, error) {
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if body != nil && req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	apiErr := &APIError{StatusCode: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(apiErr); err != nil || apiErr.Message == "" {
+		apiErr.Message = http.StatusText(resp.StatusCode)
+	}
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		apiErr.RetryAfter = time.Duration(seconds) * time.Second
+	}
+	return nil, apiErr
+}
+
+// IsNotFound reports whether err is a 404 from the server
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// Selectors returns the server's active selector pack with per-selector
+// hit counts, showing which selector variants still match the meeting UI
+func (c *Client) Selectors(ctx context.Context) (*SelectorPackStatus, error) {
+	var status SelectorPackStatus
+	if err := c.doJSON(ctx, http.MethodGet, "/selectors", nil, nil, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
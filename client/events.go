@@ -0,0 +1,58 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// StreamEvents calls fn for each event of a job, starting after lastEventID
+// (0 replays the whole history), until the job finishes, ctx is cancelled or
+// fn returns an error. It returns the ID of the last event seen so callers can
+// resume after a dropped connection.
+func (c *Client) StreamEvents(ctx context.Context, id string, lastEventID int64, fn func(Event) error) (int64, error) {
+	header := http.Header{"Accept": {"text/event-stream"}}
+	if lastEventID > 0 {
+		header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+	}
+	resp, err := c.do(ctx, http.MethodGet, "/meetings/"+url.PathEscape(id)+"/events", nil, nil, header)
+	if err != nil {
+		return lastEventID, err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var event Event
+			if err := json.Unmarshal([]byte(data.String()), &event); err != nil {
+				return lastEventID, err
+			}
+			data.Reset()
+			lastEventID = event.ID
+			if err := fn(event); err != nil {
+				return lastEventID, err
+			}
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return lastEventID, err
+	}
+	return lastEventID, scanner.Err()
+}
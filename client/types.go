@@ -0,0 +1,273 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// JobStatus is a stage in a meeting job's lifecycle
+type JobStatus string
+
+const (
+	StatusScheduled    JobStatus = "scheduled"
+	StatusQueued       JobStatus = "queued"
+	StatusLaunching    JobStatus = "launching"
+	StatusJoining      JobStatus = "joining"
+	StatusInLobby      JobStatus = "in_lobby"
+	StatusRecording    JobStatus = "recording"
+	StatusTranscribing JobStatus = "transcribing"
+	StatusSummarizing  JobStatus = "summarizing"
+	StatusDelivered    JobStatus = "delivered"
+	StatusFailed       JobStatus = "failed"
+	StatusCancelled    JobStatus = "cancelled"
+)
+
+// Terminal reports whether a job in this status will not change again
+func (s JobStatus) Terminal() bool {
+	return s == StatusDelivered || s == StatusFailed || s == StatusCancelled
+}
+
+// Duration is a time.Duration sent as a Go duration string such as "5m"
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\" or a number of seconds")
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MeetingRequest asks for a bot to join a meeting
+type MeetingRequest struct {
+	MeetingURL  string     `json:"meeting_url"`
+	BotName     string     `json:"bot_name"`
+	GuestEmail  string     `json:"email,omitempty"`
+	GuestName   string     `json:"name,omitempty"`
+	CallbackURL string     `json:"callback_url,omitempty"`
+	Passcode    string     `json:"passcode,omitempty"`
+	Locale      string     `json:"locale,omitempty"`
+	StartAt     *time.Time `json:"start_at,omitempty"`
+	JoinEarly   Duration   `json:"join_early,omitempty"`
+}
+
+// RescheduleRequest moves a scheduled join to a new time
+type RescheduleRequest struct {
+	StartAt   time.Time `json:"start_at"`
+	JoinEarly *Duration `json:"join_early,omitempty"`
+}
+
+// StartMeetingResponse is returned when a job is accepted
+type StartMeetingResponse struct {
+	JobID         string     `json:"job_id"`
+	Status        JobStatus  `json:"status"`
+	StatusURL     string     `json:"status_url"`
+	QueuePosition int        `json:"queue_position"`
+	LaunchAt      *time.Time `json:"launch_at,omitempty"`
+	// Attached is set when the request joined a bot already in the meeting
+	Attached bool `json:"attached,omitempty"`
+	// TargetIgnored is set when the request attached to a bot waiting for a
+	// different person, so its email and name have no effect
+	TargetIgnored bool `json:"target_ignored,omitempty"`
+	// Replayed is set when an earlier request with the same idempotency
+	// key was returned instead of starting a new job
+	Replayed bool `json:"-"`
+}
+
+// StatusChange records when a job entered a status
+type StatusChange struct {
+	Status JobStatus `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// DeliveryAttempt is one try at posting the completion webhook
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	URL        string    `json:"url"`
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// Job is the state of a meeting job
+type Job struct {
+	ID              string            `json:"job_id"`
+	Status          JobStatus         `json:"status"`
+	Request         MeetingRequest    `json:"request"`
+	Upload          *UploadInfo       `json:"upload,omitempty"`
+	APIKeyID        string            `json:"api_key_id,omitempty"`
+	Error           string            `json:"error,omitempty"`
+	CreatedAt       time.Time         `json:"created_at"`
+	UpdatedAt       time.Time         `json:"updated_at"`
+	FinishedAt      *time.Time        `json:"finished_at,omitempty"`
+	History         []StatusChange    `json:"history"`
+	Artifacts       map[string]string `json:"artifacts,omitempty"`
+	Versions        []ArtifactVersion `json:"versions,omitempty"`
+	Deliveries      []DeliveryAttempt `json:"deliveries,omitempty"`
+	Reprocess       *ReprocessRequest `json:"reprocess,omitempty"`
+	RecordedSeconds float64           `json:"recorded_seconds,omitempty"`
+	// RecordingLimitSeconds is how much of the recorded-minutes quota was
+	// left when the bot started recording, 0 when unlimited
+	RecordingLimitSeconds float64 `json:"recording_limit_seconds,omitempty"`
+}
+
+// MeetingStatus is a job plus its place in the wait queue
+type MeetingStatus struct {
+	Job
+	QueuePosition int `json:"queue_position"`
+}
+
+// MeetingList is one page of ListMeetings
+type MeetingList struct {
+	Meetings   []Job  `json:"meetings"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListOptions filters ListMeetings. Zero values are not sent.
+type ListOptions struct {
+	Statuses   []JobStatus
+	From, To   time.Time
+	BotName    string
+	MeetingURL string
+	Email      string
+	APIKey     string
+	Limit      int
+	Cursor     string
+}
+
+func (o ListOptions) query() url.Values {
+	q := url.Values{}
+	if len(o.Statuses) > 0 {
+		statuses := make([]string, len(o.Statuses))
+		for i, s := range o.Statuses {
+			statuses[i] = string(s)
+		}
+		q.Set("status", strings.Join(statuses, ","))
+	}
+	if !o.From.IsZero() {
+		q.Set("from", o.From.Format(time.RFC3339))
+	}
+	if !o.To.IsZero() {
+		q.Set("to", o.To.Format(time.RFC3339))
+	}
+	for name, value := range map[string]string{
+		"bot_name":    o.BotName,
+		"meeting_url": o.MeetingURL,
+		"email":       o.Email,
+		"api_key":     o.APIKey,
+		"cursor":      o.Cursor,
+	} {
+		if value != "" {
+			q.Set(name, value)
+		}
+	}
+	if o.Limit > 0 {
+		q.Set("limit", strconv.Itoa(o.Limit))
+	}
+	return q
+}
+
+// Event is an entry in a job's event stream
+type Event struct {
+	ID      int64          `json:"id"`
+	JobID   string         `json:"job_id"`
+	Type    string         `json:"type"`
+	Time    time.Time      `json:"time"`
+	Message string         `json:"message,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// FieldError describes a problem with one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// WebhookPayload is the body POSTed to a job's callback URL when it finishes
+type WebhookPayload struct {
+	JobID      string            `json:"job_id"`
+	Status     JobStatus         `json:"status"`
+	MeetingURL string            `json:"meeting_url"`
+	Transcript string            `json:"transcript,omitempty"`
+	Summary    string            `json:"summary,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Artifacts  map[string]string `json:"artifacts,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// ReprocessRequest reruns transcription and/or summarization of a finished
+// job. Empty fields use the server defaults; no stages means both.
+type ReprocessRequest struct {
+	Stages             []string `json:"stages,omitempty"`
+	TranscriptionModel string   `json:"transcription_model,omitempty"`
+	Language           string   `json:"language,omitempty"`
+	SummaryModel       string   `json:"summary_model,omitempty"`
+	Prompt             string   `json:"prompt,omitempty"`
+}
+
+// ArtifactVersion is one generation of a transcript or summary
+type ArtifactVersion struct {
+	Kind      string    `json:"kind"`
+	Version   int       `json:"version"`
+	Path      string    `json:"path"`
+	Model     string    `json:"model,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Prompt    string    `json:"prompt,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// UploadInfo describes a recording that was uploaded instead of recorded by a bot
+type UploadInfo struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+// CheckResult is the outcome of checking one server dependency
+type CheckResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// HealthReport is returned by Health and Ready
+type HealthReport struct {
+	Status    string        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// SelectorHits is one selector in a fallback chain and how often it matched
+type SelectorHits struct {
+	Selector string `json:"selector"`
+	Hits     uint64 `json:"hits"`
+}
+
+// SelectorPackStatus is the server's active selector pack, returned by Selectors
+type SelectorPackStatus struct {
+	Version   string                    `json:"version"`
+	Source    string                    `json:"source"`
+	LoadedAt  time.Time                 `json:"loaded_at"`
+	Selectors map[string][]SelectorHits `json:"selectors"`
+	// Locales holds chains for other UI languages, tried before Selectors
+	Locales map[string]map[string][]SelectorHits `json:"locales,omitempty"`
+}
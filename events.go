@@ -0,0 +1,157 @@
+package main
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType identifies something that happened to a job
+type EventType string
+
+const (
+	EventStatus           EventType = "status"
+	EventJoined           EventType = "joined"
+	EventAdmitted         EventType = "admitted"
+	EventTargetLeft       EventType = "target_left"
+	EventExitTimerStarted EventType = "exit_timer_started"
+	EventExitTimerReset   EventType = "exit_timer_reset"
+	EventMeetingEnded     EventType = "meeting_ended"
+	EventRecordingStopped EventType = "recording_stopped"
+	EventTranscriptReady  EventType = "transcript_ready"
+	EventSummaryReady     EventType = "summary_ready"
+)
+
+const (
+	// maxEventHistory bounds the events kept per job for late subscribers
+	maxEventHistory = 500
+	// subscriberBuffer is how many events a slow subscriber may fall behind
+	// before it is disconnected
+	subscriberBuffer = 64
+)
+
+// Event is a single entry in a job's event stream
+type Event struct {
+	ID      int64          `json:"id"`
+	JobID   string         `json:"job_id"`
+	Type    EventType      `json:"type"`
+	Time    time.Time      `json:"time"`
+	Message string         `json:"message,omitempty"`
+	Data    map[string]any `json:"data,omitempty"`
+}
+
+// jobStream holds the history and live subscribers of one job
+type jobStream struct {
+	nextID      int64
+	history     []Event
+	subscribers map[chan Event]struct{}
+	closed      bool
+}
+
+// EventBus fans job events out to subscribers and keeps a short history so
+// that clients connecting late can catch up
+type EventBus struct {
+	mu      sync.Mutex
+	streams map[string]*jobStream
+}
+
+// NewEventBus creates an empty event bus
+func NewEventBus() *EventBus {
+	return &EventBus{streams: make(map[string]*jobStream)}
+}
+
+func (b *EventBus) streamLocked(jobID string) *jobStream {
+	stream, ok := b.streams[jobID]
+	if !ok {
+		stream = &jobStream{subscribers: make(map[chan Event]struct{})}
+		b.streams[jobID] = stream
+	}
+	return stream
+}
+
+// Publish records an event for a job and sends it to every subscriber
+func (b *EventBus) Publish(jobID string, eventType EventType, message string, data map[string]any) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.streamLocked(jobID)
+	stream.nextID++
+	event := Event{
+		ID:      stream.nextID,
+		JobID:   jobID,
+		Type:    eventType,
+		Time:    time.Now(),
+		Message: message,
+		Data:    data,
+	}
+	stream.history = append(stream.history, event)
+	if len(stream.history) > maxEventHistory {
+		stream.history = stream.history[len(stream.history)-maxEventHistory:]
+	}
+
+	for ch := range stream.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// Too far behind, drop the subscriber so it reconnects and replays
+			delete(stream.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe returns the events published so far and a channel of future
+// events for a job. The channel is closed when the job's stream ends.
+func (b *EventBus) Subscribe(jobID string) ([]Event, <-chan Event, func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.streamLocked(jobID)
+	history := append([]Event(nil), stream.history...)
+	ch := make(chan Event, subscriberBuffer)
+	if stream.closed {
+		close(ch)
+		return history, ch, func() {}
+	}
+	stream.subscribers[ch] = struct{}{}
+
+	unsubscribe := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := stream.subscribers[ch]; ok {
+			delete(stream.subscribers, ch)
+			close(ch)
+		}
+	}
+	return history, ch, unsubscribe
+}
+
+// Close ends a job's stream and disconnects its subscribers
+func (b *EventBus) Close(jobID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stream := b.streamLocked(jobID)
+	stream.closed = true
+	for ch := range stream.subscribers {
+		delete(stream.subscribers, ch)
+		close(ch)
+	}
+}
+
+// Emit publishes a job event and logs its message
+func (j *Job) Emit(eventType EventType, message string, data map[string]any) {
+	if message != "" {
+		j.Logger().Info(message, "event", eventType)
+	}
+	if j.store != nil && j.store.events != nil {
+		j.store.events.Publish(j.ID(), eventType, message, data)
+	}
+}
+
+// Reopen lets a job's stream take subscribers again after Close, for jobs
+// that start a new run such as reprocessing
+func (b *EventBus) Reopen(jobID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.streamLocked(jobID).closed = false
+}
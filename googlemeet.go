@@ -0,0 +1,153 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// meetCodePattern matches a Google Meet meeting code such as abc-defg-hij
+var meetCodePattern = regexp.MustCompile(`^[a-z]{3}-[a-z]{4}-[a-z]{3}$`)
+
+// googleMeet drives the Google Meet web client. Its selectors come from
+// the selector pack, in the set for the browser's locale.
+type googleMeet struct {
+	locale string
+}
+
+func (googleMeet) Name() string {
+	return "Google Meet"
+}
+
+func (googleMeet) Matches(u *url.URL) bool {
+	return strings.ToLower(u.Hostname()) == "meet.google.com"
+}
+
+// NormalizeURL returns the meeting URL in canonical form,
+// https://meet.google.com/abc-defg-hij, without query or fragment
+func (googleMeet) NormalizeURL(u *url.URL) (string, error) {
+	code := strings.ToLower(strings.Trim(u.Path, "/"))
+	if !meetCodePattern.MatchString(code) {
+		return "", errors.New("must contain a meeting code like abc-defg-hij")
+	}
+	return "https://meet.google.com/" + code, nil
+}
+
+// WithLocale returns the adapter for a browser whose UI is in locale
+func (g googleMeet) WithLocale(locale string) MeetingPlatform {
+	g.locale = locale
+	return g
+}
+
+// ShowsEmails is true because signed-in participants are listed with their email
+func (googleMeet) ShowsEmails() bool {
+	return true
+}
+
+// Join fills in the bot's name and joins the call, or asks to join when
+// the meeting has a lobby
+func (g googleMeet) Join(logger *slog.Logger, page playwright.Page, botName, _ string) (bool, error) {
+	// Fill in name if the field is available
+	if selector, ok := findSelector(page, g.locale, "meet.name_input"); ok {
+		if err := page.Locator(selector).First().Fill(botName); err != nil {
+			logger.Warn("Could not fill name", "error", err)
+		} else {
+			logger.Info("Entered guest name")
+		}
+	}
+
+	// Click "Got it" button if visible
+	clickSelector(logger, page, g.locale, "meet.got_it", "Got it")
+
+	// Ensure microphone and camera are off
+	clickSelector(logger, page, g.locale, "meet.microphone_off", "Turn off microphone")
+	clickSelector(logger, page, g.locale, "meet.camera_off", "Turn off camera")
+
+	// Try to join the meeting
+	if clickSelector(logger, page, g.locale, "meet.join_now", "Join now") {
+		return false, nil
+	}
+	if !clickSelector(logger, page, g.locale, "meet.ask_to_join", "Ask to join") {
+		return false, fmt.Errorf("could not find any join button")
+	}
+
+	logger.Info("Requested to join the meeting")
+	return true, nil
+}
+
+// Admission reports whether the bot has been let in or turned away
+func (g googleMeet) Admission(page playwright.Page) (admitted, denied bool) {
+	_, admitted = findSelector(page, g.locale, "meet.in_call")
+	_, denied = findSelector(page, g.locale, "meet.join_denied")
+	return admitted, denied
+}
+
+// MeetingEnded reports whether the page shows that the bot is no longer in the call
+func (g googleMeet) MeetingEnded(page playwright.Page) (string, bool) {
+	return findSelector(page, g.locale, "meet.meeting_ended")
+}
+
+// IsPersonInMeeting checks if a specific person is present in the meeting,
+// by email or name in the participants panel or on their video tile
+func (g googleMeet) IsPersonInMeeting(logger *slog.Logger, page playwright.Page, personEmail string, personName string) bool {
+	// Try to find the participant panel first (if not already open)
+	g.openParticipantPanel(logger, page)
+
+	for _, template := range uiSelectors.Chain("meet.participant", g.locale) {
+		selector, ok := fillSelector(template, personEmail, personName)
+		if !ok {
+			continue
+		}
+		if isElementVisible(page.Locator(selector).First()) {
+			uiSelectors.Hit("meet.participant", template)
+			return true
+		}
+	}
+	return false
+}
+
+// openParticipantPanel attempts to open the participants panel if not already open
+func (g googleMeet) openParticipantPanel(logger *slog.Logger, page playwright.Page) {
+	// First, try to dismiss any popups that might be blocking the UI
+	g.dismissPopups(logger, page)
+
+	if _, open := findSelector(page, g.locale, "meet.people_panel"); open {
+		return
+	}
+	if clickSelector(logger, page, g.locale, "meet.people_button", "Show everyone") {
+		logger.Info("Opened participants panel")
+	}
+}
+
+// dismissPopups handles any popups that might appear during the meeting
+func (g googleMeet) dismissPopups(logger *slog.Logger, page playwright.Page) {
+	for _, selector := range uiSelectors.Chain("meet.popup_dismiss", g.locale) {
+		button := page.Locator(selector).First()
+		if !isElementVisible(button) {
+			continue
+		}
+		logger.Info("Found popup dismiss button", "selector", selector)
+		if err := button.Click(); err != nil {
+			logger.Warn("Failed to click dismiss button", "error", err)
+			continue
+		}
+		uiSelectors.Hit("meet.popup_dismiss", selector)
+		// Wait a moment for the popup to disappear
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// Leave attempts to exit the meeting gracefully
+func (g googleMeet) Leave(logger *slog.Logger, page playwright.Page) {
+	if clickSelector(logger, page, g.locale, "meet.leave", "Leave call") {
+		logger.Info("Left the meeting")
+		return
+	}
+	logger.Warn("Could not find leave meeting button, closing page instead")
+}
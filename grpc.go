@@ -0,0 +1,338 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "meetai/meetingbotpb"
+)
+
+//go:generate protoc -I proto --go_out=. --go_opt=module=meetai --go-grpc_out=. --go-grpc_opt=module=meetai meetingbot/v1/meetingbot.proto
+
+// grpcServer implements the MeetingBot gRPC service on top of the same job
+// store and scheduler as the HTTP handlers
+type grpcServer struct {
+	pb.UnimplementedMeetingBotServer
+}
+
+// newGRPCServer creates a gRPC server with API key authentication
+func newGRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(authUnary),
+		grpc.StreamInterceptor(authStream),
+	)
+	pb.RegisterMeetingBotServer(srv, &grpcServer{})
+	return srv
+}
+
+// stopGRPC lets in-flight calls finish, then closes any streams still open
+// after timeout
+func stopGRPC(srv *grpc.Server, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		srv.Stop()
+	}
+}
+
+func (s *grpcServer) StartMeeting(ctx context.Context, in *pb.StartMeetingRequest) (*pb.StartMeetingResponse, error) {
+	req := meetingRequestFromProto(in)
+	if err := req.Validate(); err != nil {
+		return nil, validationStatus(err)
+	}
+
+	var idempotencyKey string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("idempotency-key"); len(values) > 0 {
+		idempotencyKey = values[0]
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, "idempotency-key must be at most %d characters", maxIdempotencyKeyLength)
+	}
+
+	resp, replayed, err := startMeeting(apiKeyFromContext(ctx), req, idempotencyKey)
+	switch {
+	case errors.Is(err, ErrIdempotencyMismatch):
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	case errors.Is(err, ErrQuotaExceeded):
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, ErrQueueFull):
+		return nil, status.Error(codes.ResourceExhausted, "Too many meetings queued, try again later")
+	case errors.Is(err, ErrShuttingDown):
+		return nil, status.Error(codes.Unavailable, "Service is shutting down, try again later")
+	case err != nil:
+		slog.Error("Error starting meeting", "meeting_url", req.MeetingURL, "error", err)
+		return nil, status.Error(codes.Internal, "Could not start the meeting")
+	}
+
+	out := &pb.StartMeetingResponse{
+		JobId:         resp.JobID,
+		Status:        string(resp.Status),
+		QueuePosition: int32(resp.QueuePosition),
+		Attached:      resp.Attached,
+		TargetIgnored: resp.TargetIgnored,
+	}
+	if replayed {
+		grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+	}
+	if resp.LaunchAt != nil {
+		out.LaunchAt = timestamppb.New(*resp.LaunchAt)
+	}
+	return out, nil
+}
+
+func (s *grpcServer) GetMeeting(ctx context.Context, in *pb.GetMeetingRequest) (*pb.Meeting, error) {
+	job, err := grpcJob(ctx, in.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+	return meetingToProto(ctx, job), nil
+}
+
+func (s *grpcServer) CancelMeeting(ctx context.Context, in *pb.CancelMeetingRequest) (*pb.Meeting, error) {
+	job, err := grpcJob(ctx, in.GetJobId())
+	if err != nil {
+		return nil, err
+	}
+	if detachSubscriber(apiKeyFromContext(ctx), job) {
+		return meetingToProto(ctx, job), nil
+	}
+	switch err := scheduler.Cancel(job, in.GetDiscardRecording()); {
+	case errors.Is(err, ErrJobProcessing):
+		return nil, status.Error(codes.FailedPrecondition, "Meeting has already been recorded and is being processed")
+	case err != nil:
+		return nil, status.Error(codes.FailedPrecondition, "Meeting job has already finished")
+	}
+	return meetingToProto(ctx, job), nil
+}
+
+func (s *grpcServer) WatchEvents(in *pb.WatchEventsRequest, stream grpc.ServerStreamingServer[pb.Event]) error {
+	job, err := grpcJob(stream.Context(), in.GetJobId())
+	if err != nil {
+		return err
+	}
+
+	history, live, unsubscribe := events.Subscribe(job.ID())
+	defer unsubscribe()
+
+	for _, event := range history {
+		if event.ID <= in.GetAfterEventId() {
+			continue
+		}
+		if err := sendEvent(stream, event); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case event, ok := <-live:
+			if !ok {
+				return nil
+			}
+			if err := sendEvent(stream, event); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sendEvent(stream grpc.ServerStreamingServer[pb.Event], event Event) error {
+	out := &pb.Event{
+		Id:      event.ID,
+		JobId:   event.JobID,
+		Type:    string(event.Type),
+		Time:    timestamppb.New(event.Time),
+		Message: event.Message,
+	}
+	if len(event.Data) > 0 {
+		normalized, err := normalizeEventData(event.Data)
+		if err != nil {
+			return status.Errorf(codes.Internal, "encoding event data: %v", err)
+		}
+		data, err := structpb.NewStruct(normalized)
+		if err != nil {
+			return status.Errorf(codes.Internal, "encoding event data: %v", err)
+		}
+		out.Data = data
+	}
+	return stream.Send(out)
+}
+
+// normalizeEventData round-trips event data through JSON so that values
+// structpb cannot encode, such as JobStatus, take their JSON form
+func normalizeEventData(data map[string]any) (map[string]any, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	err = json.Unmarshal(raw, &normalized)
+	return normalized, err
+}
+
+// grpcJob looks up a job the caller may see, answering NotFound otherwise
+func grpcJob(ctx context.Context, id string) (*Job, error) {
+	job, ok := jobs.Get(id)
+	if !ok || !keyCanAccess(apiKeyFromContext(ctx), job) {
+		return nil, status.Error(codes.NotFound, "Meeting job not found")
+	}
+	return job, nil
+}
+
+// validationStatus turns a ValidationError into InvalidArgument with a
+// BadRequest detail listing each field problem
+func validationStatus(err error) error {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	details := &errdetails.BadRequest{}
+	for _, f := range verr.Fields {
+		details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       f.Field,
+			Description: f.Message,
+		})
+	}
+	st, detailErr := status.New(codes.InvalidArgument, verr.Error()).WithDetails(details)
+	if detailErr != nil {
+		return status.Error(codes.InvalidArgument, verr.Error())
+	}
+	return st.Err()
+}
+
+func meetingRequestFromProto(in *pb.StartMeetingRequest) MeetingRequest {
+	req := MeetingRequest{
+		MeetingURL:  in.GetMeetingUrl(),
+		BotName:     in.GetBotName(),
+		GuestEmail:  in.GetEmail(),
+		GuestName:   in.GetName(),
+		CallbackURL: in.GetCallbackUrl(),
+		Passcode:    in.GetPasscode(),
+		Locale:      in.GetLocale(),
+	}
+	if in.StartAt != nil {
+		startAt := in.StartAt.AsTime()
+		req.StartAt = &startAt
+	}
+	if in.JoinEarly != nil {
+		req.JoinEarly = Duration(in.JoinEarly.AsDuration())
+	}
+	return req
+}
+
+func meetingRequestToProto(req MeetingRequest) *pb.StartMeetingRequest {
+	out := &pb.StartMeetingRequest{
+		MeetingUrl:  req.MeetingURL,
+		BotName:     req.BotName,
+		Email:       req.GuestEmail,
+		Name:        req.GuestName,
+		CallbackUrl: req.CallbackURL,
+		Passcode:    req.Passcode,
+		Locale:      req.Locale,
+	}
+	if req.StartAt != nil {
+		out.StartAt = timestamppb.New(*req.StartAt)
+	}
+	if req.JoinEarly != 0 {
+		out.JoinEarly = durationpb.New(time.Duration(req.JoinEarly))
+	}
+	return out
+}
+
+// meetingToProto converts a job as the caller's API key may see it
+func meetingToProto(ctx context.Context, job *Job) *pb.Meeting {
+	info := job.InfoFor(apiKeyFromContext(ctx))
+	out := &pb.Meeting{
+		JobId:                 info.ID,
+		Status:                string(info.Status),
+		Request:               meetingRequestToProto(info.Request),
+		ApiKeyId:              info.APIKeyID,
+		Error:                 info.Error,
+		CreatedAt:             timestamppb.New(info.CreatedAt),
+		UpdatedAt:             timestamppb.New(info.UpdatedAt),
+		RecordedSeconds:       info.RecordedSeconds,
+		QueuePosition:         int32(scheduler.Position(info.ID)),
+		RecordingLimitSeconds: info.RecordingLimitSeconds,
+	}
+	if info.FinishedAt != nil {
+		out.FinishedAt = timestamppb.New(*info.FinishedAt)
+	}
+	for _, change := range info.History {
+		out.History = append(out.History, &pb.StatusChange{
+			Status: string(change.Status),
+			At:     timestamppb.New(change.At),
+		})
+	}
+	if links := artifactLinks(info); len(links) > 0 {
+		out.Artifacts = make(map[string]string, len(links))
+		for kind, link := range links {
+			out.Artifacts[string(kind)] = link
+		}
+	}
+	return out
+}
+
+// authUnary and authStream require the same bearer tokens as the HTTP API,
+// sent in the "authorization" metadata
+func authUnary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := authenticateGRPC(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func authStream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authenticateGRPC(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: ss, ctx: ctx})
+}
+
+func authenticateGRPC(ctx context.Context) (context.Context, error) {
+	if apiKeys == nil {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, value := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(value, "Bearer ")
+		if !ok {
+			continue
+		}
+		if key, valid := apiKeys.Authenticate(strings.TrimSpace(token)); valid {
+			return context.WithValue(ctx, apiKeyContextKey{}, key), nil
+		}
+	}
+	return nil, status.Error(codes.Unauthenticated, "A valid API key is required")
+}
+
+// authenticatedStream carries the caller's key in the stream context
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+
+	"meetai/ollama"
+)
+
+const (
+	// healthCheckTimeout bounds a single dependency check; importing whisper
+	// can take several seconds on a cold start
+	healthCheckTimeout = 20 * time.Second
+	// readinessCacheTTL is how long /readyz reuses its last result so that
+	// frequent probes do not keep spawning processes
+	readinessCacheTTL = 30 * time.Second
+)
+
+// CheckResult is the outcome of checking one dependency
+type CheckResult struct {
+	Name       string `json:"name"`
+	OK         bool   `json:"ok"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// HealthReport is the body of /healthz and /readyz
+type HealthReport struct {
+	Status    string        `json:"status"`
+	CheckedAt time.Time     `json:"checked_at"`
+	Checks    []CheckResult `json:"checks"`
+}
+
+// dependency is something a bot needs. installed is a cheap check that it
+// is present, works verifies that it actually runs.
+type dependency struct {
+	name      string
+	installed func(ctx context.Context) error
+	works     func(ctx context.Context) error
+}
+
+var dependencies = []dependency{
+	{"pulseaudio", checkPulseAudio, checkPulseAudio},
+	{"pactl", lookPath("pactl"), runs("pactl", "--version")},
+	{"ffmpeg", lookPath("ffmpeg"), runs("ffmpeg", "-hide_banner", "-version")},
+	{"whisper", fileExists(pythonPath), runs(pythonPath, "-c", "import whisper")},
+	{"ollama", lookPath("ollama"), func(ctx context.Context) error { return ollama.CheckModel(ctx, ollama.DefaultModel) }},
+	{"playwright", checkPlaywrightDriver, checkPlaywrightDriver},
+	{"chromium", checkChromium, checkChromium},
+}
+
+var readiness struct {
+	mu     sync.Mutex
+	report HealthReport
+	// refreshing is closed when the running refresh finishes, nil if none runs
+	refreshing chan struct{}
+}
+
+// handleHealthz reports whether every dependency is installed
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	report := runChecks(r.Context(), func(d dependency) func(context.Context) error { return d.installed })
+	writeHealthReport(w, report)
+}
+
+// handleReadyz reports whether every dependency works and the service is
+// taking new jobs
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	report, ok := cachedReadiness(r.Context())
+	if !ok {
+		report = HealthReport{Status: "unavailable", CheckedAt: time.Now(), Checks: []CheckResult{
+			{Name: "dependencies", Error: "checks are still running"},
+		}}
+	}
+
+	// Not cached so a shutdown is reported straight away
+	accepting := CheckResult{Name: "scheduler", OK: scheduler.Accepting()}
+	if !accepting.OK {
+		accepting.Error = "shutting down"
+		report.Status = "unavailable"
+	}
+	report.Checks = append(report.Checks, accepting)
+	writeHealthReport(w, report)
+}
+
+// cachedReadiness returns the last readiness report and starts a refresh in
+// the background once it is stale. The checks do not run on the request
+// context, so a probe that gives up early does not cancel them and get the
+// failure cached. Only the very first report is waited for, while ctx lasts.
+func cachedReadiness(ctx context.Context) (HealthReport, bool) {
+	readiness.mu.Lock()
+	if time.Since(readiness.report.CheckedAt) > readinessCacheTTL && readiness.refreshing == nil {
+		done := make(chan struct{})
+		readiness.refreshing = done
+		go func() {
+			report := runChecks(context.Background(), func(d dependency) func(context.Context) error { return d.works })
+			readiness.mu.Lock()
+			readiness.report = report
+			readiness.refreshing = nil
+			readiness.mu.Unlock()
+			close(done)
+		}()
+	}
+	pending := readiness.refreshing
+	first := readiness.report.CheckedAt.IsZero()
+	readiness.mu.Unlock()
+
+	if first {
+		select {
+		case <-pending:
+		case <-ctx.Done():
+			return HealthReport{}, false
+		}
+	}
+
+	readiness.mu.Lock()
+	defer readiness.mu.Unlock()
+	report := readiness.report
+	report.Checks = append([]CheckResult(nil), report.Checks...)
+	return report, true
+}
+
+// runChecks runs one check per dependency in parallel
+func runChecks(ctx context.Context, pick func(dependency) func(context.Context) error) HealthReport {
+	report := HealthReport{Status: "ok", CheckedAt: time.Now(), Checks: make([]CheckResult, len(dependencies))}
+
+	var wg sync.WaitGroup
+	for i, dep := range dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+
+			start := time.Now()
+			result := CheckResult{Name: dep.name, OK: true}
+			if err := pick(dep)(checkCtx); err != nil {
+				result.OK = false
+				result.Error = err.Error()
+			}
+			result.DurationMs = time.Since(start).Milliseconds()
+			report.Checks[i] = result
+		}()
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if !result.OK {
+			report.Status = "unavailable"
+		}
+	}
+	return report
+}
+
+func writeHealthReport(w http.ResponseWriter, report HealthReport) {
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Cache-Control", "no-store")
+	writeJSON(w, status, report)
+}
+
+// checkPulseAudio verifies that the PulseAudio daemon answers
+func checkPulseAudio(ctx context.Context) error {
+	if err := runs("pactl", "info")(ctx); err != nil {
+		return fmt.Errorf("PulseAudio is not running: %v", err)
+	}
+	return nil
+}
+
+// checkPlaywrightDriver verifies that the Playwright driver matching this
+// build of playwright-go is installed and starts
+func checkPlaywrightDriver(ctx context.Context) error {
+	driver, err := playwright.NewDriver()
+	if err != nil {
+		return err
+	}
+	output, err := commandOutput(ctx, driver.Command("--version"))
+	if err != nil {
+		return fmt.Errorf("driver not installed, run \"go run github.com/playwright-community/playwright-go/cmd/playwright install chromium\": %v", err)
+	}
+	if !strings.Contains(output, driver.Version) {
+		return fmt.Errorf("driver version %q does not match the expected %s", strings.TrimSpace(output), driver.Version)
+	}
+	return nil
+}
+
+// checkChromium verifies that the Chromium build the driver expects has been downloaded
+func checkChromium(ctx context.Context) error {
+	driver, err := playwright.NewDriver()
+	if err != nil {
+		return err
+	}
+	output, err := commandOutput(ctx, driver.Command("install", "--dry-run", "chromium"))
+	if err != nil {
+		return fmt.Errorf("could not ask the Playwright driver for the Chromium location: %v", err)
+	}
+
+	var locations []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		if location, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "Install location:"); ok {
+			locations = append(locations, strings.TrimSpace(location))
+		}
+	}
+	if len(locations) == 0 {
+		return errors.New("the Playwright driver did not report a Chromium location")
+	}
+	for _, location := range locations {
+		if _, err := os.Stat(location); err != nil {
+			return fmt.Errorf("Chromium is not installed at %s", location)
+		}
+	}
+	return nil
+}
+
+// lookPath returns a check that a binary is on the PATH
+func lookPath(name string) func(context.Context) error {
+	return func(context.Context) error {
+		_, err := exec.LookPath(name)
+		return err
+	}
+}
+
+// fileExists returns a check that a file is present
+func fileExists(path string) func(context.Context) error {
+	return func(context.Context) error {
+		_, err := os.Stat(path)
+		return err
+	}
+}
+
+// runs returns a check that a command exits successfully
+func runs(name string, args ...string) func(context.Context) error {
+	return func(ctx context.Context) error {
+		_, err := commandOutput(ctx, exec.Command(name, args...))
+		return err
+	}
+}
+
+// commandOutput runs cmd, killing it when ctx ends, and includes the tail
+// of its output in the error when it fails
+func commandOutput(ctx context.Context, cmd *exec.Cmd) (string, error) {
+	start := time.Now()
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+	done := make(chan error, 1)
+	go func() { done <- cmd.Wait() }()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return output.String(), fmt.Errorf("%v: %s", err, lastLine(output.String()))
+		}
+		return output.String(), nil
+	case <-ctx.Done():
+		cmd.Process.Kill()
+		<-done
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return output.String(), fmt.Errorf("timed out after %v", time.Since(start).Round(time.Second))
+		}
+		return output.String(), fmt.Errorf("check cancelled: %v", ctx.Err())
+	}
+}
+
+// lastLine returns the last non-empty line of command output
+func lastLine(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
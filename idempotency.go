@@ -0,0 +1,216 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	// idempotencyTTL is how long a retried request with the same
+	// Idempotency-Key gets the original job back
+	idempotencyTTL = 24 * time.Hour
+	// maxIdempotencyKeyLength bounds the Idempotency-Key header
+	maxIdempotencyKeyLength = 255
+)
+
+// ErrIdempotencyMismatch is returned when an Idempotency-Key is reused with
+// a different request body
+var ErrIdempotencyMismatch = errors.New("Idempotency-Key was already used with a different request")
+
+// Subscriber is a request that attached to a job already running in the
+// same meeting instead of launching a second bot. It gets its own webhook
+// and can read the job, without the creator's private details.
+type Subscriber struct {
+	APIKeyID       string    `json:"api_key_id,omitempty"`
+	CallbackURL    string    `json:"callback_url,omitempty"`
+	IdempotencyKey string    `json:"idempotency_key,omitempty"`
+	RequestHash    string    `json:"request_hash,omitempty"`
+	AttachedAt     time.Time `json:"attached_at"`
+	// TargetIgnored is set when the request asked to wait for someone other
+	// than the person the bot was launched for
+	TargetIgnored bool `json:"target_ignored,omitempty"`
+}
+
+// requestHash fingerprints a validated request so a reused Idempotency-Key
+// with a different body can be detected
+func requestHash(req MeetingRequest) string {
+	data, _ := json.Marshal(req)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetIdempotencyKey records the key and request fingerprint of the request
+// that created the job
+func (j *Job) SetIdempotencyKey(key, hash string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.IdempotencyKey = key
+	j.info.RequestHash = hash
+	j.saveLocked()
+}
+
+// sameTarget reports whether two requests wait for the same person
+func sameTarget(a, b MeetingRequest) bool {
+	return strings.EqualFold(a.GuestEmail, b.GuestEmail) && strings.EqualFold(a.GuestName, b.GuestName)
+}
+
+// Attach adds a subscriber to the job
+func (j *Job) Attach(sub Subscriber) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Subscribers = append(j.info.Subscribers, sub)
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// Detach removes an API key's subscriptions and reports whether it had any
+func (j *Job) Detach(apiKeyID string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	kept := j.info.Subscribers[:0]
+	for _, sub := range j.info.Subscribers {
+		if sub.APIKeyID != apiKeyID {
+			kept = append(kept, sub)
+		}
+	}
+	if len(kept) == len(j.info.Subscribers) {
+		return false
+	}
+	j.info.Subscribers = kept
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+	return true
+}
+
+// SubscribedBy reports whether an API key attached to the job
+func (j *Job) SubscribedBy(apiKeyID string) bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info.subscribedBy(apiKeyID)
+}
+
+func (info *JobInfo) subscribedBy(apiKeyID string) bool {
+	for _, sub := range info.Subscribers {
+		if sub.APIKeyID == apiKeyID {
+			return true
+		}
+	}
+	return false
+}
+
+// InfoFor returns the job state as an API key may see it. Keys that only
+// attached to the job do not see the owner's key, target, passcode,
+// webhook, upload or reprocessing settings, nor webhook deliveries to
+// anyone else. Artifacts are given as download links and versions without
+// their prompt or path.
+func (j *Job) InfoFor(key *APIKey) JobInfo {
+	info := j.Info()
+	if key == nil || key.Admin || info.APIKeyID == key.ID {
+		return info
+	}
+
+	own := make(map[string]bool)
+	for _, sub := range info.Subscribers {
+		if sub.APIKeyID == key.ID {
+			own[sub.CallbackURL] = true
+		}
+	}
+	var deliveries []DeliveryAttempt
+	for _, attempt := range info.Deliveries {
+		if own[attempt.URL] {
+			deliveries = append(deliveries, attempt)
+		}
+	}
+	info.APIKeyID = ""
+	info.Request = MeetingRequest{
+		MeetingURL: info.Request.MeetingURL,
+		BotName:    info.Request.BotName,
+		Locale:     info.Request.Locale,
+		StartAt:    info.Request.StartAt,
+		JoinEarly:  info.Request.JoinEarly,
+	}
+	info.Deliveries = deliveries
+	info.Upload = nil
+	info.Reprocess = nil
+	info.Artifacts = artifactLinks(info)
+	versions := make([]ArtifactVersion, 0, len(info.Versions))
+	for _, version := range info.Versions {
+		version.Path = ""
+		version.Prompt = ""
+		versions = append(versions, version)
+	}
+	info.Versions = versions
+	return info
+}
+
+// CallbackURLs returns every distinct webhook URL of the job's creator and subscribers
+func (j *Job) CallbackURLs() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	var urls []string
+	seen := make(map[string]bool)
+	add := func(u string) {
+		if u != "" && !seen[u] {
+			seen[u] = true
+			urls = append(urls, u)
+		}
+	}
+	add(j.info.Request.CallbackURL)
+	for _, sub := range j.info.Subscribers {
+		add(sub.CallbackURL)
+	}
+	return urls
+}
+
+// idempotentMatch is a job found through a request's Idempotency-Key
+type idempotentMatch struct {
+	job *Job
+	// hash is the fingerprint of the request that used the key first
+	hash string
+	// attached is set when that request attached to the job as a subscriber
+	attached bool
+	// targetIgnored is set when the attached request's target was ignored
+	targetIgnored bool
+}
+
+// FindIdempotent returns the job an API key created or attached to with an
+// Idempotency-Key in the last idempotencyTTL. The TTL of a subscriber runs
+// from when it attached.
+func (s *JobStore) FindIdempotent(apiKeyID, key string) (idempotentMatch, bool) {
+	cutoff := time.Now().Add(-idempotencyTTL)
+	for _, job := range s.List() {
+		info := job.Info()
+		if info.IdempotencyKey == key && info.APIKeyID == apiKeyID && !info.CreatedAt.Before(cutoff) {
+			return idempotentMatch{job: job, hash: info.RequestHash}, true
+		}
+		for _, sub := range info.Subscribers {
+			if sub.IdempotencyKey == key && sub.APIKeyID == apiKeyID && !sub.AttachedAt.Before(cutoff) {
+				return idempotentMatch{job: job, hash: sub.RequestHash, attached: true, targetIgnored: sub.TargetIgnored}, true
+			}
+		}
+	}
+	return idempotentMatch{}, false
+}
+
+// FindActive returns a job whose bot is queued for or already in a meeting
+func (s *JobStore) FindActive(meetingURL string) (*Job, bool) {
+	for _, job := range s.List() {
+		if botActive(job.Status()) && job.Request().MeetingURL == meetingURL {
+			return job, true
+		}
+	}
+	return nil, false
+}
+
+// detachSubscriber removes a caller that attached to someone else's job and
+// reports whether it did. Owners and admin keys cancel the job instead.
+func detachSubscriber(key *APIKey, job *Job) bool {
+	if ownsJob(key, job) {
+		return false
+	}
+	return job.Detach(key.ID)
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// withKeys installs API keys for the tokens and a scheduler for the test
+func withKeys(t *testing.T, tokens map[string]*APIKey) {
+	t.Helper()
+	savedKeys, savedScheduler := apiKeys, scheduler
+	store := &KeyStore{byHash: make(map[string]*APIKey)}
+	for token, key := range tokens {
+		key.Hash = hashToken(token)
+		store.byHash[key.Hash] = key
+	}
+	apiKeys = store
+	scheduler = NewScheduler(1, 0, 1, nil)
+	t.Cleanup(func() { apiKeys, scheduler = savedKeys, savedScheduler })
+}
+
+// getJSON fetches a path through the API routes with a bearer token
+func getJSON(t *testing.T, token, path string) (int, string) {
+	t.Helper()
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	requireAPIKey(mux).ServeHTTP(rec, req)
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestSubscriberSeesRedactedJob(t *testing.T) {
+	owner, subscriber := &APIKey{ID: "owner-team"}, &APIKey{ID: "other-team"}
+	withKeys(t, map[string]*APIKey{"owner-token": owner, "other-token": subscriber})
+
+	job := jobs.Create(MeetingRequest{
+		MeetingURL:  "https://meet.google.com/abc-defg-hij",
+		BotName:     "Notetaker",
+		GuestEmail:  "ceo@owner.example",
+		GuestName:   "Owner CEO",
+		CallbackURL: "https://owner.example/hook",
+		Passcode:    "s3cret-passcode",
+	}, owner.ID)
+	t.Cleanup(func() { jobs.Delete(job.ID()) })
+	job.SetStatus(StatusRecording)
+	job.SetArtifact(ArtifactRecording, "/var/lib/meetai/recordings/owner.mp3")
+	job.AddVersion(ArtifactVersion{Kind: ArtifactSummary, Version: 1, Path: "/var/lib/meetai/summaries/owner.md", Prompt: "owner summary prompt"})
+	job.mu.Lock()
+	job.info.Reprocess = &ReprocessRequest{Prompt: "owner reprocess prompt"}
+	job.mu.Unlock()
+
+	resp, _, err := startMeeting(subscriber, MeetingRequest{
+		MeetingURL:  "https://meet.google.com/abc-defg-hij",
+		BotName:     "Other bot",
+		GuestEmail:  "someone@other.example",
+		GuestName:   "Someone Else",
+		CallbackURL: "https://other.example/hook",
+	}, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.JobID != job.ID() || !resp.Attached || !resp.TargetIgnored {
+		t.Fatalf("startMeeting = %+v, want attached to %s with the target ignored", resp, job.ID())
+	}
+
+	code, body := getJSON(t, "other-token", "/meetings/"+job.ID())
+	if code != http.StatusOK {
+		t.Fatalf("subscriber GET = %d %s", code, body)
+	}
+	for _, private := range []string{
+		"owner-team", "ceo@owner.example", "Owner CEO", "owner.example/hook", "s3cret-passcode",
+		"/var/lib/meetai", "owner summary prompt", "owner reprocess prompt", "idempotency_key", "subscribers",
+	} {
+		if strings.Contains(body, private) {
+			t.Errorf("subscriber sees %q in %s", private, body)
+		}
+	}
+	if !strings.Contains(body, "/meetings/"+job.ID()+"/recording") {
+		t.Errorf("subscriber gets no recording link in %s", body)
+	}
+
+	code, body = getJSON(t, "owner-token", "/meetings/"+job.ID())
+	if code != http.StatusOK || !strings.Contains(body, "ceo@owner.example") || !strings.Contains(body, "owner summary prompt") {
+		t.Errorf("owner GET = %d %s, want the full job", code, body)
+	}
+}
+
+func TestSubscriberIdempotencyRunsFromAttaching(t *testing.T) {
+	job := jobs.Create(MeetingRequest{MeetingURL: "https://meet.google.com/abc-defg-hij"}, "owner-team")
+	t.Cleanup(func() { jobs.Delete(job.ID()) })
+	job.mu.Lock()
+	job.info.CreatedAt = time.Now().Add(-23 * time.Hour)
+	job.mu.Unlock()
+
+	tests := []struct {
+		name       string
+		attachedAt time.Time
+		want       bool
+	}{
+		{"attached an hour ago to an old job", time.Now().Add(-time.Hour), true},
+		{"attached longer ago than the TTL", time.Now().Add(-idempotencyTTL - time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key := "retry-" + tt.name
+			job.Attach(Subscriber{APIKeyID: "other-team", IdempotencyKey: key, AttachedAt: tt.attachedAt})
+			match, ok := jobs.FindIdempotent("other-team", key)
+			if ok != tt.want || (ok && (match.job != job || !match.attached)) {
+				t.Errorf("FindIdempotent = %+v, %v; want found %v", match, ok, tt.want)
+			}
+		})
+	}
+}
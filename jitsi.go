@@ -0,0 +1,167 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// jitsiHosts are the hosts whose meeting URLs are treated as Jitsi Meet.
+// Jitsi is often self-hosted, so the list is set with -jitsi-hosts.
+var jitsiHosts = []string{"meet.jit.si"}
+
+// jitsiRoomPattern matches a Jitsi room name, optionally under a tenant
+// such as "team/standup"
+var jitsiRoomPattern = regexp.MustCompile(`^([a-z0-9][a-z0-9_.-]*/)?[a-z0-9][a-z0-9_.-]{0,99}$`)
+
+// jitsiJoinTimeout is how long Join waits for the meeting or lobby to load
+// after clicking the join button
+const jitsiJoinTimeout = 30 * time.Second
+
+// setJitsiHosts parses a comma separated host list such as
+// "meet.jit.si,meet.example.com,localhost:8443"
+func setJitsiHosts(list string) {
+	jitsiHosts = nil
+	for _, host := range strings.Split(list, ",") {
+		if host = strings.ToLower(strings.TrimSpace(host)); host != "" {
+			jitsiHosts = append(jitsiHosts, host)
+		}
+	}
+}
+
+// jitsiMeet drives the Jitsi Meet web client
+type jitsiMeet struct{}
+
+func (jitsiMeet) Name() string {
+	return "Jitsi Meet"
+}
+
+// Matches accepts URLs on a configured host, with or without its port
+func (jitsiMeet) Matches(u *url.URL) bool {
+	host, hostname := strings.ToLower(u.Host), strings.ToLower(u.Hostname())
+	for _, h := range jitsiHosts {
+		if h == host || h == hostname {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeURL returns the meeting URL as scheme://host/room with the room
+// name lower-cased, as Jitsi treats room names case-insensitively. Config
+// overrides in the fragment are dropped. The scheme is kept so that a local
+// deployment can be reached over plain http.
+func (jitsiMeet) NormalizeURL(u *url.URL) (string, error) {
+	room := strings.ToLower(strings.Trim(u.Path, "/"))
+	if !jitsiRoomPattern.MatchString(room) {
+		return "", errors.New("must contain a room name like my-meeting")
+	}
+	return u.Scheme + "://" + strings.ToLower(u.Host) + "/" + room, nil
+}
+
+// ShowsEmails is false because Jitsi only lists display names
+func (jitsiMeet) ShowsEmails() bool {
+	return false
+}
+
+var (
+	jitsiInMeeting = []string{
+		"[aria-label='Leave the meeting']",
+		"[aria-label='Leave']",
+		".hangup-button",
+	}
+	jitsiInLobby = []string{
+		"text=/Asking to join meeting/i",
+		"text=/Waiting for the host/i",
+		"[data-testid='lobby.knockingParticipant']",
+	}
+)
+
+// Join fills in the bot's name on the prejoin screen and joins. Rooms with
+// the lobby enabled, or that wait for a moderator, leave the bot in the lobby.
+func (jitsiMeet) Join(logger *slog.Logger, page playwright.Page, botName, _ string) (bool, error) {
+	nameInput := page.Locator("#premeeting-name-input")
+	if isElementVisible(nameInput) {
+		if err := nameInput.Fill(botName); err != nil {
+			logger.Warn("Could not fill name", "error", err)
+		} else {
+			logger.Info("Entered guest name")
+		}
+	}
+
+	// Join with microphone and camera off
+	handleButton(logger, page, "[aria-label='Mute']", "Mute")
+	handleButton(logger, page, "[aria-label='Stop camera']", "Stop camera")
+
+	// Servers with the prejoin screen disabled put the bot straight into the meeting
+	if !handleButton(logger, page, "[data-testid='prejoin.joinMeeting']", "Join meeting") &&
+		!handleButton(logger, page, "[aria-label='Join meeting']", "Join meeting") {
+		if _, ok := firstVisible(page, jitsiInMeeting...); !ok {
+			return false, errors.New("could not find the join button")
+		}
+	}
+
+	deadline := time.Now().Add(jitsiJoinTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := firstVisible(page, jitsiInMeeting...); ok {
+			return false, nil
+		}
+		if _, ok := firstVisible(page, jitsiInLobby...); ok {
+			logger.Info("Requested to join the meeting")
+			return true, nil
+		}
+		time.Sleep(time.Second)
+	}
+	return false, errors.New("the meeting did not load after joining")
+}
+
+// Admission reports whether the bot has been let in or turned away
+func (jitsiMeet) Admission(page playwright.Page) (admitted, denied bool) {
+	_, admitted = firstVisible(page, jitsiInMeeting...)
+	_, denied = firstVisible(page,
+		"text=/request was rejected/i",
+		"text=/request to join was declined/i",
+	)
+	return admitted, denied
+}
+
+// MeetingEnded reports whether the bot was removed, the meeting was ended
+// for everyone or the page moved to the post-meeting screen
+func (jitsiMeet) MeetingEnded(page playwright.Page) (string, bool) {
+	return firstVisible(page,
+		"text=/You have been (kicked out|removed)/i",
+		"text=/meeting (has been|was) (ended|terminated)/i",
+		"text=/Thank you for using/i",
+		"#close-page",
+	)
+}
+
+// IsPersonInMeeting looks for the person's display name in the
+// participants pane and on the video tiles. Jitsi does not show emails, so
+// the email is only matched if the person used it as their name.
+func (j jitsiMeet) IsPersonInMeeting(logger *slog.Logger, page playwright.Page, email, name string) bool {
+	j.openParticipantsPane(logger, page)
+
+	roster := []string{"[id^='participant-item-']", ".displayname"}
+	return containsText(page, name, roster...) || containsText(page, email, roster...)
+}
+
+// openParticipantsPane opens the participants pane unless it is already open
+func (jitsiMeet) openParticipantsPane(logger *slog.Logger, page playwright.Page) {
+	if _, open := firstVisible(page, ".participants_pane", "#participants-pane"); open {
+		return
+	}
+	if clickFirstVisible(logger, page, "Participants", "[aria-label='Open participants pane']", "[aria-label='Participants']") {
+		time.Sleep(time.Second)
+	}
+}
+
+// Leave clicks the hang up button
+func (jitsiMeet) Leave(logger *slog.Logger, page playwright.Page) {
+	leaveByButton(logger, page, jitsiInMeeting...)
+}
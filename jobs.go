@@ -0,0 +1,497 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// JobStatus is a step in the lifecycle of a meeting bot job
+type JobStatus string
+
+const (
+	StatusScheduled    JobStatus = "scheduled"
+	StatusQueued       JobStatus = "queued"
+	StatusLaunching    JobStatus = "launching"
+	StatusJoining      JobStatus = "joining"
+	StatusInLobby      JobStatus = "in_lobby"
+	StatusRecording    JobStatus = "recording"
+	StatusTranscribing JobStatus = "transcribing"
+	StatusSummarizing  JobStatus = "summarizing"
+	StatusDelivered    JobStatus = "delivered"
+	StatusFailed       JobStatus = "failed"
+	StatusCancelled    JobStatus = "cancelled"
+)
+
+// Terminal reports whether a job in this status will not change any more
+func (s JobStatus) Terminal() bool {
+	return s == StatusDelivered || s == StatusFailed || s == StatusCancelled
+}
+
+// Processing reports whether the meeting is over and its recording is going
+// through the pipeline, when cancelling can no longer change anything
+func (s JobStatus) Processing() bool {
+	return s == StatusTranscribing || s == StatusSummarizing
+}
+
+// ArtifactKind names a file produced by a job
+type ArtifactKind string
+
+const (
+	ArtifactRecording  ArtifactKind = "recording"
+	ArtifactTranscript ArtifactKind = "transcript"
+	ArtifactSummary    ArtifactKind = "summary"
+	ArtifactLog        ArtifactKind = "log"
+)
+
+// StatusChange records when a job entered a status
+type StatusChange struct {
+	Status JobStatus `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+// ArtifactVersion is one generation of a transcript or summary. Reprocessing
+// adds versions instead of overwriting earlier output.
+type ArtifactVersion struct {
+	Kind      ArtifactKind `json:"kind"`
+	Version   int          `json:"version"`
+	Path      string       `json:"path"`
+	Model     string       `json:"model,omitempty"`
+	Language  string       `json:"language,omitempty"`
+	Prompt    string       `json:"prompt,omitempty"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// JobInfo is the externally visible state of a job
+type JobInfo struct {
+	ID         string         `json:"job_id"`
+	Status     JobStatus      `json:"status"`
+	Request    MeetingRequest `json:"request"`
+	Upload     *UploadInfo    `json:"upload,omitempty"`
+	APIKeyID   string         `json:"api_key_id,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	CreatedAt  time.Time      `json:"created_at"`
+	UpdatedAt  time.Time      `json:"updated_at"`
+	FinishedAt *time.Time     `json:"finished_at,omitempty"`
+	History    []StatusChange `json:"history"`
+
+	Artifacts  map[ArtifactKind]string `json:"artifacts,omitempty"`
+	Versions   []ArtifactVersion       `json:"versions,omitempty"`
+	Deliveries []DeliveryAttempt       `json:"deliveries,omitempty"`
+
+	// Reprocess is the reprocessing run in progress, kept so it can resume after a restart
+	Reprocess *ReprocessRequest `json:"reprocess,omitempty"`
+
+	// IdempotencyKey and RequestHash let a retried request find this job.
+	// Subscribers are later requests for the same meeting that share its bot.
+	// They are internal and only persisted, through storedJob.
+	IdempotencyKey string       `json:"-"`
+	RequestHash    string       `json:"-"`
+	Subscribers    []Subscriber `json:"-"`
+
+	RecordedSeconds float64 `json:"recorded_seconds,omitempty"`
+	// RecordingLimitSeconds is how much of the API key's recorded-minutes
+	// quota was left when the bot started recording. The bot stops earlier
+	// if the key's other bots use it up first.
+	RecordingLimitSeconds float64 `json:"recording_limit_seconds,omitempty"`
+}
+
+// Job tracks a single meeting bot run from the API request to delivery
+type Job struct {
+	mu      sync.Mutex
+	info    JobInfo
+	discard bool
+	store   *JobStore
+	logger  *slog.Logger
+}
+
+// ID returns the job's identifier
+func (j *Job) ID() string {
+	return j.info.ID
+}
+
+// APIKeyID returns the ID of the API key that launched the job
+func (j *Job) APIKeyID() string {
+	return j.info.APIKeyID
+}
+
+// Request returns the meeting request the job was created from
+func (j *Job) Request() MeetingRequest {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info.Request
+}
+
+// Status returns the job's current status
+func (j *Job) Status() JobStatus {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.info.Status
+}
+
+// Info returns a copy of the job state that is safe to serialize
+func (j *Job) Info() JobInfo {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	info := j.info
+	info.History = append([]StatusChange(nil), j.info.History...)
+	info.Deliveries = append([]DeliveryAttempt(nil), j.info.Deliveries...)
+	info.Versions = append([]ArtifactVersion(nil), j.info.Versions...)
+	info.Subscribers = append([]Subscriber(nil), j.info.Subscribers...)
+	if j.info.Artifacts != nil {
+		info.Artifacts = make(map[ArtifactKind]string, len(j.info.Artifacts))
+		for kind, path := range j.info.Artifacts {
+			info.Artifacts[kind] = path
+		}
+	}
+	return info
+}
+
+// SetArtifact records the path of a file produced by the job
+func (j *Job) SetArtifact(kind ArtifactKind, path string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.Artifacts == nil {
+		j.info.Artifacts = make(map[ArtifactKind]string)
+	}
+	j.info.Artifacts[kind] = path
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// ClearArtifact forgets a file that no longer exists
+func (j *Job) ClearArtifact(kind ArtifactKind) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	delete(j.info.Artifacts, kind)
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// NextVersion returns the version number the next transcript or summary of
+// this kind will get
+func (j *Job) NextVersion(kind ArtifactKind) int {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	latest := 0
+	for _, v := range j.info.Versions {
+		if v.Kind == kind {
+			latest = max(latest, v.Version)
+		}
+	}
+	// Jobs created before versioning have an unrecorded first version
+	if _, ok := j.info.Artifacts[kind]; ok && latest == 0 {
+		latest = 1
+	}
+	return latest + 1
+}
+
+// AddVersion records a new transcript or summary and makes it the current artifact
+func (j *Job) AddVersion(v ArtifactVersion) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now()
+	}
+	if j.info.Artifacts == nil {
+		j.info.Artifacts = make(map[ArtifactKind]string)
+	}
+	// Keep the unrecorded first version of jobs created before versioning
+	if previous, ok := j.info.Artifacts[v.Kind]; ok && v.Version == 2 {
+		recorded := false
+		for _, existing := range j.info.Versions {
+			recorded = recorded || existing.Kind == v.Kind
+		}
+		if !recorded {
+			j.info.Versions = append(j.info.Versions, ArtifactVersion{Kind: v.Kind, Version: 1, Path: previous, CreatedAt: j.info.CreatedAt})
+		}
+	}
+	j.info.Versions = append(j.info.Versions, v)
+	j.info.Artifacts[v.Kind] = v.Path
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// ArtifactVersion returns the path of a specific transcript or summary version
+func (j *Job) ArtifactVersion(kind ArtifactKind, version int) (string, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	for _, v := range j.info.Versions {
+		if v.Kind == kind && v.Version == version {
+			return v.Path, true
+		}
+	}
+	return "", false
+}
+
+// Artifact returns the path of a file produced by the job, if any
+func (j *Job) Artifact(kind ArtifactKind) (string, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	path, ok := j.info.Artifacts[kind]
+	return path, ok
+}
+
+// AddDelivery records a completion webhook delivery attempt
+func (j *Job) AddDelivery(attempt DeliveryAttempt) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Deliveries = append(j.info.Deliveries, attempt)
+	j.saveLocked()
+}
+
+// SetStatus moves the job to a new status. Terminal jobs are left untouched.
+func (j *Job) SetStatus(status JobStatus) {
+	j.mu.Lock()
+	changed := j.setStatusLocked(status)
+	j.mu.Unlock()
+
+	if changed {
+		j.publishStatus(status, "")
+	}
+}
+
+// cancelBot stops the job's bot through cancel unless the job is over or
+// its recording is already being processed. The status is checked and the
+// bot cancelled under the job lock, so the bot cannot start processing in
+// between.
+func (j *Job) cancelBot(cancel context.CancelFunc, discard bool) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	switch {
+	case j.info.Status.Processing():
+		return ErrJobProcessing
+	case j.info.Status.Terminal():
+		return ErrJobFinished
+	}
+	j.discard = discard
+	cancel()
+	return nil
+}
+
+// DiscardRecording reports whether the partial recording should be thrown away
+func (j *Job) DiscardRecording() bool {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.discard
+}
+
+// SetRecordingLimit records how long the bot may record at most, 0 meaning no limit
+func (j *Job) SetRecordingLimit(limit time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.RecordingLimitSeconds = limit.Seconds()
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// enteredAt returns when the job last entered a status
+func (info *JobInfo) enteredAt(status JobStatus) (time.Time, bool) {
+	for i := len(info.History) - 1; i >= 0; i-- {
+		if info.History[i].Status == status {
+			return info.History[i].At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// SetRecordedDuration records how long the bot was recording in the meeting
+func (j *Job) SetRecordedDuration(d time.Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.RecordedSeconds = d.Seconds()
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// SetSchedule changes when a scheduled job should start
+func (j *Job) SetSchedule(startAt time.Time, joinEarly Duration) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Request.StartAt = &startAt
+	j.info.Request.JoinEarly = joinEarly
+	j.info.UpdatedAt = time.Now()
+	j.saveLocked()
+}
+
+// Fail marks the job as failed with the given error
+func (j *Job) Fail(err error) {
+	j.mu.Lock()
+	if j.info.Status.Terminal() {
+		j.mu.Unlock()
+		return
+	}
+	j.info.Error = err.Error()
+	j.setStatusLocked(StatusFailed)
+	j.mu.Unlock()
+
+	j.publishStatus(StatusFailed, err.Error())
+}
+
+// publishStatus emits a status event and ends the event stream once the job
+// reaches a terminal status
+func (j *Job) publishStatus(status JobStatus, errMessage string) {
+	if errMessage != "" {
+		j.Logger().Error("Status changed", "status", status, "error", errMessage)
+	} else {
+		j.Logger().Info("Status changed", "status", status)
+	}
+	if j.store == nil || j.store.events == nil {
+		return
+	}
+	data := map[string]any{"status": status}
+	if errMessage != "" {
+		data["error"] = errMessage
+	}
+	j.store.events.Publish(j.ID(), EventStatus, "", data)
+	if status.Terminal() {
+		j.store.events.Close(j.ID())
+	}
+}
+
+// saveLocked persists the job state if the store is backed by a database
+func (j *Job) saveLocked() {
+	if j.store == nil || j.store.db == nil {
+		return
+	}
+	if err := j.store.db.Save(j.info); err != nil {
+		slog.Error("Error saving job", "job_id", j.info.ID, "error", err)
+	}
+}
+
+// setStatusLocked updates the status and reports whether it changed
+func (j *Job) setStatusLocked(status JobStatus) bool {
+	if j.info.Status.Terminal() || j.info.Status == status {
+		return false
+	}
+	now := time.Now()
+	j.info.Status = status
+	j.info.UpdatedAt = now
+	j.info.History = append(j.info.History, StatusChange{Status: status, At: now})
+	if status.Terminal() {
+		j.info.FinishedAt = &now
+	}
+	j.saveLocked()
+	return true
+}
+
+// JobStore keeps track of every job created by the API
+type JobStore struct {
+	mu     sync.RWMutex
+	jobs   map[string]*Job
+	events *EventBus
+	db     *JobDB
+}
+
+// NewJobStore creates an empty in-memory job store that publishes job
+// events on the given bus
+func NewJobStore(events *EventBus) *JobStore {
+	return &JobStore{jobs: make(map[string]*Job), events: events}
+}
+
+// Create registers a new job for the request on behalf of an API key.
+// Requests that start in the future begin as scheduled, everything else as
+// queued.
+func (s *JobStore) Create(req MeetingRequest, apiKeyID string) *Job {
+	status := StatusQueued
+	if req.LaunchAt().After(time.Now()) {
+		status = StatusScheduled
+	}
+	return s.create(req, apiKeyID, status, nil)
+}
+
+// CreateUpload registers a job for an uploaded recording, which starts
+// straight at transcription
+func (s *JobStore) CreateUpload(req MeetingRequest, apiKeyID string, upload UploadInfo) *Job {
+	return s.create(req, apiKeyID, StatusTranscribing, &upload)
+}
+
+func (s *JobStore) create(req MeetingRequest, apiKeyID string, status JobStatus, upload *UploadInfo) *Job {
+	now := time.Now()
+	job := &Job{info: JobInfo{
+		ID:        newJobID(),
+		Status:    status,
+		Request:   req,
+		APIKeyID:  apiKeyID,
+		CreatedAt: now,
+		UpdatedAt: now,
+		History:   []StatusChange{{Status: status, At: now}},
+		Upload:    upload,
+	}, store: s}
+
+	job.mu.Lock()
+	job.saveLocked()
+	job.mu.Unlock()
+
+	s.mu.Lock()
+	s.jobs[job.ID()] = job
+	s.mu.Unlock()
+	return job
+}
+
+// Restore attaches a database to the store and loads every job saved in it.
+// Jobs saved from now on are written to the database as they change.
+func (s *JobStore) Restore(db *JobDB) ([]*Job, error) {
+	infos, err := db.LoadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.db = db
+	restored := make([]*Job, 0, len(infos))
+	for _, info := range infos {
+		job := &Job{info: info, store: s}
+		s.jobs[info.ID] = job
+		if info.Status.Terminal() && s.events != nil {
+			s.events.Close(info.ID)
+		}
+		restored = append(restored, job)
+	}
+	return restored, nil
+}
+
+// Get looks up a job by ID
+func (s *JobStore) Get(id string) (*Job, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	return job, ok
+}
+
+// List returns every job in the store
+func (s *JobStore) List() []*Job {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	list := make([]*Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		list = append(list, job)
+	}
+	return list
+}
+
+// Delete forgets a job
+func (s *JobStore) Delete(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	if s.db != nil {
+		if err := s.db.Delete(id); err != nil {
+			slog.Error("Error deleting job", "job_id", id, "error", err)
+		}
+	}
+}
+
+// newJobID generates a random, URL-safe job identifier
+func newJobID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate job ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// MeetingListResponse is one page of GET /meetings
+type MeetingListResponse struct {
+	Meetings   []JobInfo `json:"meetings"`
+	NextCursor string    `json:"next_cursor,omitempty"`
+}
+
+// jobFilter selects jobs for GET /meetings
+type jobFilter struct {
+	statuses   map[JobStatus]bool
+	from, to   time.Time
+	botName    string
+	meetingURL string
+	email      string
+	apiKeyID   string
+}
+
+// matches reports whether a job passes every filter that was set
+func (f jobFilter) matches(info JobInfo) bool {
+	if len(f.statuses) > 0 && !f.statuses[info.Status] {
+		return false
+	}
+	if !f.from.IsZero() && info.CreatedAt.Before(f.from) {
+		return false
+	}
+	if !f.to.IsZero() && !info.CreatedAt.Before(f.to) {
+		return false
+	}
+	if f.botName != "" && !strings.Contains(strings.ToLower(info.Request.BotName), f.botName) {
+		return false
+	}
+	if f.meetingURL != "" && info.Request.MeetingURL != f.meetingURL {
+		return false
+	}
+	if f.email != "" && !strings.EqualFold(info.Request.GuestEmail, f.email) {
+		return false
+	}
+	if f.apiKeyID != "" && info.APIKeyID != f.apiKeyID && !info.subscribedBy(f.apiKeyID) {
+		return false
+	}
+	return true
+}
+
+// parseJobFilter reads the listing filters from the query string
+func parseJobFilter(r *http.Request) (jobFilter, error) {
+	q := r.URL.Query()
+	verr := &ValidationError{}
+	f := jobFilter{
+		botName: strings.ToLower(strings.TrimSpace(q.Get("bot_name"))),
+		email:   strings.TrimSpace(q.Get("email")),
+	}
+
+	if statuses := q.Get("status"); statuses != "" {
+		f.statuses = make(map[JobStatus]bool)
+		for _, s := range strings.Split(statuses, ",") {
+			f.statuses[JobStatus(strings.TrimSpace(s))] = true
+		}
+	}
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{{"from", &f.from}, {"to", &f.to}} {
+		if value := q.Get(bound.name); value != "" {
+			t, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				verr.add(bound.name, "must be an RFC 3339 timestamp")
+			}
+			*bound.dst = t
+		}
+	}
+	if meetingURL := q.Get("meeting_url"); meetingURL != "" {
+		normalized, err := normalizeMeetingURL(meetingURL)
+		if err != nil {
+			verr.add("meeting_url", "%v", err)
+		}
+		f.meetingURL = normalized
+	}
+
+	// Keys only see their own jobs unless they are admin keys
+	key := apiKeyFrom(r)
+	f.apiKeyID = q.Get("api_key")
+	if key != nil && !key.Admin {
+		if f.apiKeyID != "" && f.apiKeyID != key.ID {
+			verr.add("api_key", "only admin keys can list other keys' meetings")
+		}
+		f.apiKeyID = key.ID
+	}
+	return f, verr.errOrNil()
+}
+
+// handleListMeetings returns a page of jobs, newest first
+func handleListMeetings(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseJobFilter(r)
+	if err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	limit := defaultPageSize
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxPageSize {
+			writeValidationError(w, &ValidationError{Fields: []FieldError{{
+				Field: "limit", Message: fmt.Sprintf("must be between 1 and %d", maxPageSize),
+			}}})
+			return
+		}
+		limit = n
+	}
+
+	var after *pageCursor
+	if value := r.URL.Query().Get("cursor"); value != "" {
+		cursor, err := decodeCursor(value)
+		if err != nil {
+			writeValidationError(w, &ValidationError{Fields: []FieldError{{Field: "cursor", Message: "is not valid"}}})
+			return
+		}
+		after = &cursor
+	}
+
+	var matched []JobInfo
+	for _, job := range jobs.List() {
+		if info := job.InfoFor(apiKeyFrom(r)); filter.matches(info) {
+			matched = append(matched, info)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return newerThan(matched[i], pageCursor{matched[j].CreatedAt, matched[j].ID})
+	})
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(matched), func(i int) bool {
+			return !newerThan(matched[i], *after) && matched[i].ID != after.ID
+		})
+	}
+	end := min(start+limit, len(matched))
+
+	resp := MeetingListResponse{Meetings: matched[start:end]}
+	if resp.Meetings == nil {
+		resp.Meetings = []JobInfo{}
+	}
+	if end < len(matched) {
+		last := matched[end-1]
+		resp.NextCursor = encodeCursor(pageCursor{last.CreatedAt, last.ID})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// pageCursor points at the last job of a page in (created_at desc, id desc) order
+type pageCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// newerThan reports whether a job sorts before the cursor position
+func newerThan(info JobInfo, c pageCursor) bool {
+	if !info.CreatedAt.Equal(c.CreatedAt) {
+		return info.CreatedAt.After(c.CreatedAt)
+	}
+	return info.ID > c.ID
+}
+
+func encodeCursor(c pageCursor) string {
+	raw := strconv.FormatInt(c.CreatedAt.UnixNano(), 10) + ":" + c.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(value string) (pageCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return pageCursor{}, err
+	}
+	nanos, id, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return pageCursor{}, fmt.Errorf("malformed cursor")
+	}
+	n, err := strconv.ParseInt(nanos, 10, 64)
+	if err != nil {
+		return pageCursor{}, err
+	}
+	return pageCursor{CreatedAt: time.Unix(0, n), ID: id}, nil
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listMeetingsURL is the meeting every job in these tests is for, so that
+// listings filtered by it only see them
+const listMeetingsURL = "https://meet.google.com/pag-inat-ion"
+
+// createdJobs creates jobs for listMeetingsURL created at the given times
+// and returns them newest first, the order GET /meetings lists them in
+func createdJobs(t *testing.T, times ...time.Time) []JobInfo {
+	t.Helper()
+	var infos []JobInfo
+	for _, at := range times {
+		job := jobs.Create(MeetingRequest{MeetingURL: listMeetingsURL}, "")
+		t.Cleanup(func() { jobs.Delete(job.ID()) })
+		job.mu.Lock()
+		job.info.CreatedAt = at
+		job.mu.Unlock()
+		infos = append(infos, job.Info())
+	}
+	slices.SortFunc(infos, func(a, b JobInfo) int {
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return b.CreatedAt.Compare(a.CreatedAt)
+		}
+		return strings.Compare(b.ID, a.ID)
+	})
+	return infos
+}
+
+// listMeetings calls GET /meetings for listMeetingsURL with extra query parameters
+func listMeetings(t *testing.T, query url.Values) (int, MeetingListResponse) {
+	t.Helper()
+	query.Set("meeting_url", listMeetingsURL)
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/meetings?"+query.Encode(), nil))
+	var resp MeetingListResponse
+	if rec.Code == http.StatusOK {
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decoding %s: %v", rec.Body, err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func jobIDs(infos []JobInfo) []string {
+	ids := make([]string, len(infos))
+	for i, info := range infos {
+		ids[i] = info.ID
+	}
+	return ids
+}
+
+func TestListMeetingsPagesThroughTies(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	want := createdJobs(t, base, base.Add(time.Minute), base.Add(time.Minute), base.Add(time.Minute), base.Add(2*time.Minute))
+
+	for _, limit := range []string{"1", "2", "3", "5"} {
+		t.Run("limit "+limit, func(t *testing.T) {
+			var got []JobInfo
+			query := url.Values{"limit": {limit}}
+			for page := 0; page < len(want)+1; page++ {
+				code, resp := listMeetings(t, query)
+				if code != http.StatusOK {
+					t.Fatalf("page %d: status %d", page, code)
+				}
+				got = append(got, resp.Meetings...)
+				if resp.NextCursor == "" {
+					break
+				}
+				query.Set("cursor", resp.NextCursor)
+			}
+			if !slices.Equal(jobIDs(got), jobIDs(want)) {
+				t.Errorf("pages list %v, want %v", jobIDs(got), jobIDs(want))
+			}
+		})
+	}
+}
+
+func TestListMeetingsFilters(t *testing.T) {
+	base := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	infos := createdJobs(t, base, base.Add(time.Minute), base.Add(2*time.Minute), base.Add(3*time.Minute))
+
+	// A cursor for a job that was deleted since still resumes after its place
+	stale := encodeCursor(pageCursor{CreatedAt: base.Add(150 * time.Second), ID: "deleted"})
+
+	tests := []struct {
+		name     string
+		query    url.Values
+		wantCode int
+		want     []JobInfo
+	}{
+		{"all", url.Values{}, http.StatusOK, infos},
+		{"stale cursor", url.Values{"cursor": {stale}}, http.StatusOK, infos[1:]},
+		{"to is exclusive", url.Values{"to": {base.Add(2 * time.Minute).Format(time.RFC3339)}}, http.StatusOK, infos[2:]},
+		{"from and to", url.Values{
+			"from": {base.Add(time.Minute).Format(time.RFC3339)},
+			"to":   {base.Add(3 * time.Minute).Format(time.RFC3339)},
+		}, http.StatusOK, infos[1:3]},
+		{"to before every job", url.Values{"to": {base.Format(time.RFC3339)}}, http.StatusOK, nil},
+		{"malformed cursor", url.Values{"cursor": {"not a cursor"}}, http.StatusBadRequest, nil},
+		{"cursor without an ID", url.Values{"cursor": {base64.RawURLEncoding.EncodeToString([]byte("1772355600000000000"))}}, http.StatusBadRequest, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, resp := listMeetings(t, tt.query)
+			if code != tt.wantCode {
+				t.Fatalf("status %d, want %d", code, tt.wantCode)
+			}
+			if code == http.StatusOK && !slices.Equal(jobIDs(resp.Meetings), jobIDs(tt.want)) {
+				t.Errorf("listed %v, want %v", jobIDs(resp.Meetings), jobIDs(tt.want))
+			}
+		})
+	}
+}
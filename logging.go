@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// logFolder holds one log file per job with everything logged for it,
+// including the output of ffmpeg, Whisper and Ollama
+const logFolder = "logs"
+
+// initLogging makes slog the default logger. Records at level and above
+// are written to stderr as text or JSON; job log files always get every
+// record including subprocess output.
+func initLogging(format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q, expected debug, info, warn or error", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch format {
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid log format %q, expected text or json", format)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// fatal logs an error and exits
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// Logger returns the job's logger. Every record carries the job ID, meeting
+// URL and bot name and is also appended to the job's log file, which is
+// served as the "log" artifact.
+func (j *Job) Logger() *slog.Logger {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.logger != nil {
+		return j.logger
+	}
+
+	path := filepath.Join(logFolder, j.info.ID+".jsonl")
+	file := slog.NewJSONHandler(&logFile{path: path}, &slog.HandlerOptions{Level: slog.LevelDebug})
+	attrs := []any{"job_id", j.info.ID}
+	if url := j.info.Request.MeetingURL; url != "" {
+		attrs = append(attrs, "meeting_url", url)
+	}
+	if name := j.info.Request.BotName; name != "" {
+		attrs = append(attrs, "bot_name", name)
+	}
+	j.logger = slog.New(teeHandler{slog.Default().Handler(), file}).With(attrs...)
+
+	if j.info.Artifacts[ArtifactLog] != path {
+		if j.info.Artifacts == nil {
+			j.info.Artifacts = make(map[ArtifactKind]string)
+		}
+		j.info.Artifacts[ArtifactLog] = path
+		j.saveLocked()
+	}
+	return j.logger
+}
+
+// logFile appends to a job's log file. The file is opened for each record
+// so that finished jobs do not hold on to file descriptors.
+type logFile struct {
+	mu   sync.Mutex
+	path string
+}
+
+func (f *logFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(f.path), os.ModePerm); err != nil {
+		return 0, err
+	}
+	file, err := os.OpenFile(f.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	n, err := file.Write(p)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return n, err
+}
+
+// teeHandler sends each record to every handler that accepts its level
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
+
+// outputLogger logs a subprocess's output line by line at debug level,
+// tagged with the program that wrote it
+type outputLogger struct {
+	mu      sync.Mutex
+	logger  *slog.Logger
+	pending []byte
+}
+
+// newOutputLogger returns a writer for the output of program
+func newOutputLogger(logger *slog.Logger, program string) *outputLogger {
+	return &outputLogger{logger: logger.With("source", program)}
+}
+
+func (o *outputLogger) Write(p []byte) (int, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.pending = append(o.pending, p...)
+	// Progress output such as ffmpeg's ends lines with a carriage return
+	for {
+		i := bytes.IndexAny(o.pending, "\r\n")
+		if i < 0 {
+			break
+		}
+		o.logLine(o.pending[:i])
+		o.pending = o.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// Flush logs output that did not end with a newline
+func (o *outputLogger) Flush() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.logLine(o.pending)
+	o.pending = nil
+}
+
+func (o *outputLogger) logLine(line []byte) {
+	if text := strings.TrimSpace(string(line)); text != "" {
+		o.logger.Debug(text)
+	}
+}
@@ -2,8 +2,11 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"math/rand"
 	"meetai/ollama"
 	"os"
@@ -20,9 +23,17 @@ const (
 	recordingFolder  = "recordings"
 	transcriptFolder = "transcripts"
 	summaryFolder    = "summaries"
+
+	// defaultWhisperModel is the model transcribe.py loads when none is chosen
+	defaultWhisperModel = "base"
+	// pythonPath is the virtualenv interpreter that has whisper installed
+	pythonPath = "./venv/bin/python"
+
+	// lobbyTimeout is how long the bot waits to be admitted after asking to join
+	lobbyTimeout = 10 * time.Minute
 )
 
-func initAudioSystem() {
+func initAudioSystem() error {
 	// Create virtual loopback device
 	// exec.Command("sudo", "modprobe", "snd-aloop").Run()
 
@@ -33,10 +44,50 @@ func initAudioSystem() {
 	exec.Command("pactl", "set-default-sample-format", "s16le").Run()
 	// Give a moment for PulseAudio to initialize
 	time.Sleep(500 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return checkPulseAudio(ctx)
 }
 
-func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestName string) error {
-	initAudioSystem()
+// RunMeetingBot joins the meeting described by the job, records it until it
+// ends and runs the recording through transcription and summarization.
+// Cancelling ctx makes the bot leave the meeting early.
+func RunMeetingBot(ctx context.Context, job *Job) (err error) {
+	req := job.Request()
+	meetingURL, botName := req.MeetingURL, req.BotName
+	guestEmail, guestName := req.GuestEmail, req.GuestName
+	logger := job.Logger()
+	platform, err := platformFor(meetingURL)
+	if err != nil {
+		return err
+	}
+	// Pin the browser language so the UI matches the selectors
+	locale := req.Locale
+	if locale == "" {
+		locale = defaultLocale
+	}
+	if localized, ok := platform.(localizedPlatform); ok {
+		platform = localized.WithLocale(locale)
+	}
+
+	if err := checkRecordingQuota(job); err != nil {
+		return err
+	}
+
+	// Count joins that fail before the bot gets into the meeting
+	admitted := false
+	joinStep := joinStepAudio
+	defer func() {
+		if !admitted && err != nil {
+			recordJoinFailure(joinStep, err)
+		}
+	}()
+
+	job.SetStatus(StatusLaunching)
+	if err := initAudioSystem(); err != nil {
+		return err
+	}
 	// Generate a unique filename with timestamp
 	// filename := fmt.Sprintf("meeting_%s.mp3", time.Now().Format("20060102_150405"))
 	// audioFilePath := filepath.Join(recordingFolder, filename)
@@ -48,7 +99,7 @@ func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestNa
 	// sinkID = strings.ReplaceAll(sinkID, "'", "") // Add this line to remove apostrophes
 
 	// Create dedicated audio sink
-	sinkName, err := createAudioSink(sinkID)
+	sinkName, err := createAudioSink(logger, sinkID)
 	if err != nil {
 		return fmt.Errorf("audio sink creation failed: %v", err)
 	}
@@ -57,6 +108,7 @@ func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestNa
 	filename := fmt.Sprintf("meeting_%s_%s.mp3",
 		time.Now().Format("20060102_150405"), sinkID)
 	audioFilePath := filepath.Join(recordingFolder, filename)
+	job.SetArtifact(ArtifactRecording, audioFilePath)
 
 	// Ensure recording directory exists
 	if err := os.MkdirAll(recordingFolder, os.ModePerm); err != nil {
@@ -73,15 +125,42 @@ func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestNa
 	// recordCmd := startRecording("test123.mp3", "VirtualMic") // or "VirtualMic.2"
 
 	monitorSource := sinkName + ".monitor"
-	recordCmd := startRecording(audioFilePath, monitorSource)
+	recordCmd, err := startRecording(logger, audioFilePath, monitorSource)
+	if err != nil {
+		destroyAudioSink(logger, sinkName)
+		return err
+	}
 
-	// Add cleanup defer
+	// Add cleanup defer. The recording is only processed once the bot has
+	// actually made it into the meeting.
+	var recordingStart time.Time
 	defer func() {
-		stopRecordingGracefully(recordCmd,audioFilePath)
-		destroyAudioSink(sinkName)
+		stopRecordingGracefully(logger, recordCmd)
+		job.Emit(EventRecordingStopped, "Recording stopped", map[string]any{"path": audioFilePath})
+		if admitted {
+			duration := time.Since(recordingStart)
+			job.SetRecordedDuration(duration)
+			meetingDuration.Observe(duration.Seconds())
+			recordRecordingSize(sourceBot, audioFilePath)
+		}
+		destroyAudioSink(logger, sinkName)
+		if ctx.Err() != nil && job.DiscardRecording() {
+			logger.Info("Discarding partial recording", "path", audioFilePath)
+			os.Remove(audioFilePath)
+			job.ClearArtifact(ArtifactRecording)
+			err = ctx.Err()
+			return
+		}
+		if !admitted {
+			return
+		}
+		if perr := processRecording(job, audioFilePath); perr != nil && err == nil {
+			err = perr
+		}
 	}()
 
 	// Initialize Playwright
+	joinStep = joinStepBrowser
 	pw, err := playwright.Run()
 	if err != nil {
 		return fmt.Errorf("failed to start Playwright: %v", err)
@@ -96,18 +175,20 @@ func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestNa
 	defer browser.Close()
 
 	// Create new page
-	page, err := browser.NewPage()
+	page, err := browser.NewPage(playwright.BrowserNewPageOptions{Locale: playwright.String(locale)})
 	if err != nil {
 		return fmt.Errorf("failed to create page: %v", err)
 	}
 
 	// Set the audio output device for the browser to our sink
 	// This is crucial for capturing the meeting audio
-	if err := setAudioOutputDevice(page, sinkName); err != nil {
-		fmt.Printf("Warning: Could not set audio output device: %v\n", err)
+	if err := setAudioOutputDevice(logger, page, sinkName); err != nil {
+		logger.Warn("Could not set audio output device", "error", err)
 	}
 
-	fmt.Printf("Joining meeting: %s as %s\n", meetingURL, botName)
+	job.SetStatus(StatusJoining)
+	joinStep = joinStepNavigate
+	logger.Info("Joining meeting")
 
 	// Navigate to the meeting URL
 	if _, err := page.Goto(meetingURL); err != nil {
@@ -117,16 +198,52 @@ func RunMeetingBot(meetingURL string, botName string, guestEmail string, guestNa
 	// Add random delay and simulate human behavior
 	randomDelay(2, 5)
 	simulateHumanBehavior(page)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 
 	// Join the meeting
-	if err := joinMeeting(page, botName); err != nil {
-		log.Printf("Error joining meeting: %v", err)
+	joinStep = joinStepJoin
+	inLobby, err := platform.Join(logger, page, botName, req.Passcode)
+	if err != nil {
+		return fmt.Errorf("error joining meeting: %v", err)
+	}
+
+	// Wait in the lobby until someone lets us in
+	if inLobby {
+		job.SetStatus(StatusInLobby)
+		lobbyStart := time.Now()
+		err := waitForAdmission(ctx, platform, page, lobbyTimeout)
+		recordLobbyWait(lobbyStart, err)
+		if err != nil {
+			if ctx.Err() != nil {
+				platform.Leave(logger, page)
+			}
+			return err
+		}
+		job.Emit(EventAdmitted, "Admitted to the meeting from the lobby", nil)
+	} else {
+		job.Emit(EventJoined, "Successfully joined the meeting", nil)
 	}
+	if joiner, ok := platform.(audioJoiner); ok {
+		if err := joiner.JoinAudio(logger, page); err != nil {
+			logger.Warn("Could not join meeting audio, the recording may be silent", "error", err)
+		}
+	}
+	admitted = true
+	recordJoinSuccess(inLobby)
+	recordingStart = time.Now()
+	job.SetStatus(StatusRecording)
+
+	// Leave once the API key's recorded-minutes quota runs out
+	monitorCtx, stopMonitor := context.WithCancelCause(ctx)
+	defer stopMonitor(nil)
+	go enforceRecordingQuota(monitorCtx, job, stopMonitor)
 
 	// Wait for meeting to end
 	var wg sync.WaitGroup
 	wg.Add(1)
-	go monitorMeetingEnd(page, recordCmd, audioFilePath, guestEmail, guestName, &wg)
+	go monitorMeetingEnd(monitorCtx, job, platform, page, recordCmd, audioFilePath, guestEmail, guestName, &wg)
 	wg.Wait()
 
 	return nil
@@ -146,11 +263,11 @@ func sanitizeName(name string) string {
 
 // setAudioOutputDevice attempts to set the audio output device for the browser
 // Modify setAudioOutputDevice in main.go
-func setAudioOutputDevice(page playwright.Page, sinkName string) error {
+func setAudioOutputDevice(logger *slog.Logger, page playwright.Page, sinkName string) error {
 	// Check if the browser supports audio output selection
 	supported, err := page.Evaluate(`() => typeof navigator.mediaDevices.selectAudioOutput === 'function'`)
 	if err != nil || !supported.(bool) {
-		fmt.Println("Audio output selection not supported, skipping...")
+		logger.Debug("Audio output selection not supported, skipping")
 		return nil
 	}
 
@@ -177,42 +294,10 @@ func simulateHumanBehavior(page playwright.Page) {
 	randomDelay(1, 2)
 }
 
-// joinMeeting handles the process of joining a Google Meet
-func joinMeeting(page playwright.Page, botName string) error {
-	// Fill in name if the field is available
-	nameInput := page.Locator("input[aria-label='Your name']")
-	if nameInput != nil {
-		isVisible, err := nameInput.IsVisible()
-		if err == nil && isVisible {
-			if err := nameInput.Fill(botName); err != nil {
-				log.Printf("Could not fill name: %v", err)
-			} else {
-				fmt.Println("Entered guest name:", botName)
-			}
-		}
-	}
-
-	// Click "Got it" button if visible
-	handleButton(page, "button:has-text('Got it')", "Got it")
-
-	// Ensure microphone and camera are off
-	handleButton(page, "[aria-label='Turn off microphone']", "Turn off microphone")
-	handleButton(page, "[aria-label='Turn off camera']", "Turn off camera")
-
-	// Try to join the meeting
-	if !handleButton(page, "button:has-text('Join now')", "Join now") {
-		if !handleButton(page, "button:has-text('Ask to join')", "Ask to join") {
-			return fmt.Errorf("could not find any join button")
-		}
-	}
-
-	fmt.Println("Successfully requested to join the meeting")
-	return nil
-}
-
-// handleButton attempts to click a button identified by selector
-func handleButton(page playwright.Page, selector string, buttonName string) bool {
-	button := page.Locator(selector)
+// handleButton attempts to click a button identified by selector. Only the
+// first match counts, as strict mode fails on a selector matching several.
+func handleButton(logger *slog.Logger, page playwright.Page, selector string, buttonName string) bool {
+	button := page.Locator(selector).First()
 	if button == nil {
 		return false
 	}
@@ -223,19 +308,22 @@ func handleButton(page playwright.Page, selector string, buttonName string) bool
 	}
 
 	if err := button.Click(); err != nil {
-		log.Printf("Could not click %s button: %v", buttonName, err)
+		logger.Warn("Could not click button", "button", buttonName, "error", err)
 		return false
 	}
 
-	fmt.Printf("Clicked %s button\n", buttonName)
+	logger.Info("Clicked button", "button", buttonName)
 	randomDelay(1, 3)
 	return true
 }
 
-// startRecording starts the FFmpeg process to record the meeting audio
-func startRecording(filepath, sourceName string) *exec.Cmd {
-	fmt.Println("Starting recording from:", sourceName)
+// startRecording starts the FFmpeg process to record the meeting audio. Its
+// output goes to the job log.
+func startRecording(logger *slog.Logger, filepath, sourceName string) (*exec.Cmd, error) {
+	logger.Info("Starting recording", "source", sourceName)
 	cmd := exec.Command("ffmpeg",
+		// Progress lines would flood the job log for long meetings
+		"-nostats",
 		"-f", "pulse",
 		"-i", sourceName,
 		// "-i", sourceName+".monitor",
@@ -246,27 +334,29 @@ func startRecording(filepath, sourceName string) *exec.Cmd {
 		filepath,
 	)
 
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	output := newOutputLogger(logger, "ffmpeg")
+	cmd.Stdout = output
+	cmd.Stderr = output
 
 	if err := cmd.Start(); err != nil {
-		log.Fatalf("Failed to start FFmpeg recording: %v", err)
+		return nil, fmt.Errorf("failed to start FFmpeg recording: %v", err)
 	}
 
-	fmt.Println("Recording started:", filepath)
-	return cmd
+	logger.Info("Recording started", "path", filepath)
+	return cmd, nil
 }
 
 // stopRecordingGracefully properly stops the FFmpeg recording process
-func stopRecordingGracefully(recordCmd *exec.Cmd, audioFilePath string) {
-	fmt.Println("Stopping recording...")
+func stopRecordingGracefully(logger *slog.Logger, recordCmd *exec.Cmd) {
+	logger.Info("Stopping recording")
 	if recordCmd.Process != nil {
 		recordCmd.Process.Signal(os.Interrupt)
 		recordCmd.Wait()
-		fmt.Println("Recording stopped")
+		if output, ok := recordCmd.Stderr.(*outputLogger); ok {
+			output.Flush()
+		}
+		logger.Info("Recording stopped")
 	}
-	processRecording(audioFilePath)
-
 }
 
 // randomDelay adds a random delay between actions to simulate human behavior
@@ -275,10 +365,11 @@ func randomDelay(min, max int) {
 	time.Sleep(time.Duration(delay) * time.Second)
 }
 
-// monitorMeetingEnd continuously checks if the user has left the meeting
-func monitorMeetingEnd(page playwright.Page, recordCmd *exec.Cmd, audioFilePath, guestEmail string, guestName string, wg *sync.WaitGroup) {
+// monitorMeetingEnd continuously checks if the user has left the meeting.
+// When ctx is cancelled the bot leaves the meeting straight away.
+func monitorMeetingEnd(ctx context.Context, job *Job, platform MeetingPlatform, page playwright.Page, recordCmd *exec.Cmd, audioFilePath, guestEmail string, guestName string, wg *sync.WaitGroup) {
 	defer wg.Done()
-	fmt.Println("Audio file path:", audioFilePath)
+	logger := job.Logger()
 
 	// Target person information
 	targetPerson := guestEmail    // Email of the person we're tracking
@@ -288,132 +379,68 @@ func monitorMeetingEnd(page playwright.Page, recordCmd *exec.Cmd, audioFilePath,
 	targetPersonLeftTime := time.Time{}
 	exitTimeoutAfterTargetLeaves := 20 * time.Second
 
-	fmt.Println("Monitoring meeting for target person:------------", targetPerson, targetPersonName)
+	logger.Info("Monitoring meeting for target person", "email", targetPerson, "name", targetPersonName)
 
 	for {
 		// Check for meeting exit indicators
-		exitIndicators := []string{
-			"text='You have left the meeting'",
-			"text='No one else is in the meeting'",
-			"button:has-text('Rejoin')",
-			"button:has-text('Return to home screen')",
-		}
-
-		for _, indicator := range exitIndicators {
-			if isElementVisible(page.Locator(indicator)) {
-				fmt.Println("Meeting ended. Stopping recording...")
-				stopRecording(recordCmd)
-				page.Close()
+		if indicator, ended := platform.MeetingEnded(page); ended {
+			job.Emit(EventMeetingEnded, "Meeting ended. Stopping recording...",
+				map[string]any{"reason": "exit_indicator", "indicator": indicator})
+			stopRecording(logger, recordCmd)
+			page.Close()
 
-				return
-			}
+			return
 		}
 		// Check if target person is still in the meeting
-		targetPresent := isPersonInMeeting(page, targetPerson, targetPersonName)
+		targetPresent := platform.IsPersonInMeeting(logger, page, targetPerson, targetPersonName)
 		if !targetPresent {
-			fmt.Println("Checking if target person is in the meeting...")
+			logger.Debug("Target person is not in the meeting")
 			// Target person is not in the meeting
 			if targetPersonLeftTime.IsZero() {
 				// First detection of target person absence, start timer
 				targetPersonLeftTime = time.Now()
-				fmt.Printf("Target person %s (%s) has left the meeting. Starting exit timer.\n",
-					targetPersonName, targetPerson)
+				target := map[string]any{"email": targetPerson, "name": targetPersonName}
+				job.Emit(EventTargetLeft, fmt.Sprintf("Target person %s (%s) has left the meeting.",
+					targetPersonName, targetPerson), target)
+				job.Emit(EventExitTimerStarted, "Starting exit timer.",
+					map[string]any{"timeout_seconds": exitTimeoutAfterTargetLeaves.Seconds()})
 			} else if time.Since(targetPersonLeftTime) > exitTimeoutAfterTargetLeaves {
 				// We've waited long enough after target person left, now exit
-				fmt.Printf("It's been %v since target person left. Leaving the meeting.\n",
-					exitTimeoutAfterTargetLeaves)
-				leaveCurrentMeeting(page)
-				stopRecording(recordCmd)
+				job.Emit(EventMeetingEnded, fmt.Sprintf("It's been %v since target person left. Leaving the meeting.",
+					exitTimeoutAfterTargetLeaves), map[string]any{"reason": "target_left"})
+				platform.Leave(logger, page)
+				stopRecording(logger, recordCmd)
 				page.Close()
 
 				return
 			}
 		} else {
-			fmt.Println("Currently in the meeting, target person is present.")
+			logger.Debug("Target person is present")
 			// Target person is back in the meeting, reset timer if needed
 			if !targetPersonLeftTime.IsZero() {
-				fmt.Printf("Target person %s (%s) is back in the meeting. Resetting exit timer.\n",
-					targetPersonName, targetPerson)
+				job.Emit(EventExitTimerReset, fmt.Sprintf("Target person %s (%s) is back in the meeting. Resetting exit timer.",
+					targetPersonName, targetPerson), map[string]any{"email": targetPerson, "name": targetPersonName})
 				targetPersonLeftTime = time.Time{}
 			}
 		}
-		time.Sleep(2 * time.Second)
-	}
-}
-
-// isPersonInMeeting checks if a specific person is present in the meeting
-func isPersonInMeeting(page playwright.Page, personEmail string, personName string) bool {
-	// Try to find the participant panel first (if not already open)
-	openParticipantPanel(page)
-
-	// Look for this person in the participants list by email or name
-	participantSelectors := []string{
-		// Check by email
-		`[aria-label*="${personEmail}"]`,
-		`text="${personEmail}"`,
-		// Check by name
-		`[aria-label*="${personName}"]`,
-		`text="${personName}"`,
-		// More general selectors that might contain the name or email
-		`div[role="listitem"]:has-text("${personEmail}")`,
-		`div[role="listitem"]:has-text("${personName}")`,
-	}
-
-	// Replace template values with actual values
-	for i, selector := range participantSelectors {
-		participantSelectors[i] = strings.Replace(selector, "${personEmail}", personEmail, -1)
-		participantSelectors[i] = strings.Replace(selector, "${personName}", personName, -1)
-	}
-
-	// Try each selector
-	for _, selector := range participantSelectors {
-		element := page.Locator(selector)
-		if element != nil {
-			visible, err := element.IsVisible()
-			if err == nil && visible {
-				count, err := element.Count()
-				if err == nil && count > 0 {
-					return true
-				}
-			}
-		}
-	}
 
-	// Check approach 2: Look at active speaker indicators or other UI elements
-	// This approach works even if we can't open the participants panel
-	activeSpeakerSelectors := []string{
-		// Look for the person's name in active speaker labels
-		`[data-active-speaker-label*="${personName}"]`,
-		// Look for their tile with name label
-		`[aria-label*="${personName}"][role="img"]`,
-		`[aria-label*="${personName}"][role="button"]`,
-		// Look in chat messages (if they sent any)
-		`[data-sender-name*="${personName}"]`,
-	}
-
-	// Replace template values with actual values
-	for i, selector := range activeSpeakerSelectors {
-		activeSpeakerSelectors[i] = strings.Replace(selector, "${personName}", personName, -1)
-	}
-
-	// Try each active speaker/participant indicator selector
-	for _, selector := range activeSpeakerSelectors {
-		element := page.Locator(selector)
-		if element != nil {
-			visible, err := element.IsVisible()
-			if err == nil && visible {
-				count, err := element.Count()
-				if err == nil && count > 0 {
-					return true
-				}
+		select {
+		case <-ctx.Done():
+			if errors.Is(context.Cause(ctx), errRecordingLimit) {
+				job.Emit(EventMeetingEnded, "Recording limit reached. Leaving the meeting...", map[string]any{"reason": "recording_limit"})
+			} else {
+				job.Emit(EventMeetingEnded, "Bot cancelled. Leaving the meeting...", map[string]any{"reason": "cancelled"})
 			}
+			platform.Leave(logger, page)
+			stopRecording(logger, recordCmd)
+			page.Close()
+			return
+		case <-time.After(2 * time.Second):
 		}
 	}
-
-	return false
 }
 
-func createAudioSink(sinkID string) (string, error) {
+func createAudioSink(logger *slog.Logger, sinkID string) (string, error) {
 	// Create a safer sink name with only alphanumeric characters
 	sinkName := fmt.Sprintf("bot_sink_%s", sinkID)
 
@@ -438,12 +465,12 @@ func createAudioSink(sinkID string) (string, error) {
 		return "", fmt.Errorf("sink creation failed: sink not found in list")
 	}
 
-	fmt.Printf("Successfully created audio sink: %s\n", sinkName)
+	logger.Info("Created audio sink", "sink", sinkName)
 	return sinkName, nil
 }
 
 // destroyAudioSink unloads the null-sink module
-func destroyAudioSink(sinkName string) error {
+func destroyAudioSink(logger *slog.Logger, sinkName string) error {
 	// Get the module ID for the sink
 	listCmd := exec.Command("pactl", "list", "short", "modules")
 	output, err := listCmd.CombinedOutput()
@@ -475,219 +502,222 @@ func destroyAudioSink(sinkName string) error {
 		return fmt.Errorf("error unloading module: %v", err)
 	}
 
-	fmt.Printf("Successfully destroyed audio sink: %s\n", sinkName)
+	logger.Info("Destroyed audio sink", "sink", sinkName)
 	return nil
 }
 
-// openParticipantPanel attempts to open the participants panel if not already open
-func openParticipantPanel(page playwright.Page) {
-	// First, try to dismiss any popups that might be blocking the UI
-	dismissPopups(page)
-
-	// Potential selectors for the participant panel button
-	participantButtonSelectors := []string{
-		`[aria-label="Show everyone"]`,
-		`[aria-label="Participants"]`,
-		`[aria-label="People"]`,
-		`button[aria-label*="participant"]`,
-		`[data-tooltip="Show everyone"]`,
-	}
-
-	for _, selector := range participantButtonSelectors {
-		button := page.Locator(selector)
-		if button != nil {
-			visible, err := button.IsVisible()
-			if err == nil && visible {
-				// Check if panel is already open
-				panelOpenSelectors := []string{
-					`[aria-label="Participants panel"]`,
-					`[aria-label="People panel"]`,
-					`div[role="dialog"]:has-text("People")`,
-				}
-
-				panelAlreadyOpen := false
-				for _, panelSelector := range panelOpenSelectors {
-					panel := page.Locator(panelSelector)
-					if panel != nil {
-						panelVisible, err := panel.IsVisible()
-						if err == nil && panelVisible {
-							panelAlreadyOpen = true
-							break
-						}
-					}
-				}
-
-				if !panelAlreadyOpen {
-					// Click to open panel
-					if err := button.Click(); err == nil {
-						fmt.Println("Opened participants panel")
-						// Wait for panel to appear
-						time.Sleep(1 * time.Second)
-						return
-					}
-				} else {
-					// Panel already open
-					return
-				}
-			}
-		}
+// cleanupAudioSinks unloads every bot sink module that is still loaded
+func cleanupAudioSinks() {
+	output, err := exec.Command("pactl", "list", "short", "modules").CombinedOutput()
+	if err != nil {
+		slog.Warn("Could not list audio modules for cleanup", "error", err)
+		return
 	}
-}
 
-// dismissPopups handles any popups that might appear during the meeting
-func dismissPopups(page playwright.Page) {
-	// List of common popup dismiss button selectors
-	dismissButtonSelectors := []string{
-		// The "Got it" button from your screenshot
-		`button:has-text("Got it")`,
-		`text="Got it"`,
-		// Other common popup buttons
-		`button:has-text("Dismiss")`,
-		`button:has-text("Close")`,
-		`button:has-text("I understand")`,
-		`button:has-text("No thanks")`,
-		`button:has-text("Skip")`,
-		`button:has-text("Not now")`,
-		// Close icons
-		`[aria-label="Close"]`,
-		`[aria-label="Dismiss"]`,
-	}
-
-	for _, selector := range dismissButtonSelectors {
-		button := page.Locator(selector)
-		if button != nil {
-			visible, err := button.IsVisible()
-			if err == nil && visible {
-				fmt.Printf("Found popup dismiss button: %s\n", selector)
-				if err := button.Click(); err != nil {
-					fmt.Printf("Failed to click dismiss button: %v\n", err)
-				} else {
-					// fmt.Println("Successfully dismissed popup")
-					// Wait a moment for the popup to disappear
-					time.Sleep(500 * time.Millisecond)
-				}
-			}
+	for _, line := range strings.Split(string(output), "\n") {
+		if !strings.Contains(line, "sink_name=bot_sink_") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if err := exec.Command("pactl", "unload-module", fields[0]).Run(); err != nil {
+			slog.Warn("Failed to unload audio module", "module", fields[0], "error", err)
+		} else {
+			slog.Info("Unloaded leftover audio module", "module", fields[0])
 		}
 	}
 }
 
-// leaveCurrentMeeting attempts to exit the meeting gracefully
-func leaveCurrentMeeting(page playwright.Page) {
-	// Click the hang up/leave meeting button
-	leaveButtons := []string{
-		"[aria-label='Leave call']",
-		"button[aria-label*='leave']",
-		"button[aria-label*='hang up']",
-		"button[data-is-muted='leave-call']",
-		// Add more potential selectors for the leave button
-	}
-
-	for _, buttonSelector := range leaveButtons {
-		button := page.Locator(buttonSelector)
-		if button != nil {
-			visible, err := button.IsVisible()
-			if err == nil && visible {
-				fmt.Println("Clicking leave meeting button...")
-				if err := button.Click(); err != nil {
-					fmt.Printf("Failed to click leave button: %v\n", err)
-				} else {
-					fmt.Println("Successfully left the meeting")
-					randomDelay(1, 2)
-					return
-				}
-			}
+// processRecording handles transcription and summarization of the audio file.
+// Stages the job already completed, e.g. before a restart, are not redone.
+func processRecording(job *Job, audioFilePath string) error {
+	time.Sleep(2 * time.Second)
+	if _, err := os.Stat(audioFilePath); os.IsNotExist(err) {
+		return fmt.Errorf("audio file not found: %s", audioFilePath)
+	}
+
+	// Reuse an existing transcript if we got that far before
+	transcript, ok := readArtifact(job, ArtifactTranscript)
+	if !ok {
+		var err error
+		transcript, err = transcribeStage(job, audioFilePath, TranscribeOptions{})
+		if err != nil {
+			return err
 		}
 	}
 
-	fmt.Println("Could not find leave meeting button. Closing page instead.")
+	if _, ok := readArtifact(job, ArtifactSummary); ok {
+		job.SetStatus(StatusDelivered)
+		return nil
+	}
+
+	if err := summarizeStage(job, audioFilePath, transcript, ollama.Options{}); err != nil {
+		return err
+	}
+
+	job.SetStatus(StatusDelivered)
+	return nil
 }
 
-// processRecording handles transcription and summarization of the audio file
-func processRecording(audioFilePath string) {
-	time.Sleep(2 * time.Second)
-	if _, err := os.Stat(audioFilePath); os.IsNotExist(err) {
-		fmt.Println("Error: Audio file not found:", audioFilePath)
-		return
+// transcribeStage transcribes the recording and stores the transcript as a
+// new version of the job's transcript
+func transcribeStage(job *Job, audioFilePath string, opts TranscribeOptions) (string, error) {
+	job.SetStatus(StatusTranscribing)
+	if opts.Model == "" {
+		opts.Model = defaultWhisperModel
 	}
-	// Transcribe the audio
-	transcript, err := transcribeAudio(audioFilePath)
+	start := time.Now()
+	transcript, err := transcribeAudio(job.Logger(), audioFilePath, opts)
 	if err != nil {
-		fmt.Println("Error transcribing audio:", err)
-		return
+		whisperErrors.Inc()
+		return "", fmt.Errorf("error transcribing audio: %v", err)
 	}
+	transcriptionLatency.Observe(time.Since(start).Seconds())
 
 	// Save transcript
-	if err := saveOutput(audioFilePath, transcriptFolder, transcript); err != nil {
-		fmt.Println("Error saving transcript:", err)
-		return
-	}
+	version := job.NextVersion(ArtifactTranscript)
+	transcriptPath, err := saveOutput(job.Logger(), audioFilePath, transcriptFolder, transcript, version)
+	if err != nil {
+		return "", fmt.Errorf("error saving transcript: %v", err)
+	}
+	job.AddVersion(ArtifactVersion{
+		Kind:     ArtifactTranscript,
+		Version:  version,
+		Path:     transcriptPath,
+		Model:    opts.Model,
+		Language: opts.Language,
+	})
+	job.Emit(EventTranscriptReady, "Transcript ready", map[string]any{"path": transcriptPath, "version": version})
+	return transcript, nil
+}
 
-	// Summarize the transcription
-	summary, err := ollama.RunOllama(transcript)
+// summarizeStage summarizes a transcript and stores the summary as a new
+// version of the job's summary
+func summarizeStage(job *Job, audioFilePath, transcript string, opts ollama.Options) error {
+	job.SetStatus(StatusSummarizing)
+	if opts.Model == "" {
+		opts.Model = ollama.DefaultModel
+	}
+	start := time.Now()
+	logger := job.Logger()
+	logger.Info("Summarizing with Ollama", "model", opts.Model)
+	output := newOutputLogger(logger, "ollama")
+	opts.Output = output
+	summary, err := ollama.Summarize(transcript, opts)
+	output.Flush()
 	if err != nil {
-		fmt.Println("Error summarizing text:", err)
-		return
+		ollamaErrors.Inc()
+		return fmt.Errorf("error summarizing text: %v", err)
 	}
+	summarizationLatency.Observe(time.Since(start).Seconds())
 
 	// Save summary
-	if err := saveOutput(audioFilePath, summaryFolder, summary); err != nil {
-		fmt.Println("Error saving summary:", err)
-		return
+	version := job.NextVersion(ArtifactSummary)
+	summaryPath, err := saveOutput(logger, audioFilePath, summaryFolder, summary, version)
+	if err != nil {
+		return fmt.Errorf("error saving summary: %v", err)
+	}
+	job.AddVersion(ArtifactVersion{
+		Kind:    ArtifactSummary,
+		Version: version,
+		Path:    summaryPath,
+		Model:   opts.Model,
+		Prompt:  opts.Prompt,
+	})
+	job.Emit(EventSummaryReady, "Summary ready", map[string]any{"path": summaryPath, "version": version})
+	return nil
+}
+
+// readArtifact loads a text artifact the job produced earlier, if it is still on disk
+func readArtifact(job *Job, kind ArtifactKind) (string, bool) {
+	path, ok := job.Artifact(kind)
+	if !ok {
+		return "", false
 	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
 }
 
 // saveOutput saves data to a file with the same base name as the audio file but in a different folder
-func saveOutput(audioFilePath, folderName, content string) error {
+// and returns the path it was written to. Versions after the first get a ".vN" suffix so
+// reprocessing never overwrites earlier output.
+func saveOutput(logger *slog.Logger, audioFilePath, folderName, content string, version int) (string, error) {
 	if err := os.MkdirAll(folderName, os.ModePerm); err != nil {
-		return fmt.Errorf("error creating folder %s: %v", folderName, err)
+		return "", fmt.Errorf("error creating folder %s: %v", folderName, err)
 	}
 
-	// Generate the output file path by replacing .mp3 with .txt
+	// Generate the output file path by replacing the audio extension with .txt
 	filename := filepath.Base(audioFilePath)
-	outputFilePath := filepath.Join(folderName, filename[:len(filename)-4]+".txt")
+	name := strings.TrimSuffix(filename, filepath.Ext(filename))
+	if version > 1 {
+		name += fmt.Sprintf(".v%d", version)
+	}
+	outputFilePath := filepath.Join(folderName, name+".txt")
 
 	if err := os.WriteFile(outputFilePath, []byte(content), 0644); err != nil {
-		return fmt.Errorf("error saving file: %v", err)
+		return "", fmt.Errorf("error saving file: %v", err)
 	}
 
-	fmt.Printf("File saved at: %s\n", outputFilePath)
-	return nil
+	logger.Info("File saved", "path", outputFilePath)
+	return outputFilePath, nil
 }
 
 // stopRecording stops the FFmpeg process
-func stopRecording(recordCmd *exec.Cmd) {
+func stopRecording(logger *slog.Logger, recordCmd *exec.Cmd) {
 	if recordCmd.Process == nil {
 		return
 	}
 
-	fmt.Println("Stopping recording gracefully...")
+	logger.Info("Stopping recording gracefully")
 	recordCmd.Process.Signal(os.Interrupt)
 
 	// Force kill if needed
 	if err := recordCmd.Process.Kill(); err != nil {
-		fmt.Printf("Failed to kill FFmpeg process: %v\n", err)
+		logger.Warn("Failed to kill FFmpeg process", "error", err)
 	} else {
-		fmt.Println("Recording process stopped.")
+		logger.Info("Recording process stopped")
 	}
 }
 
-// transcribeAudio runs the Python transcription script
-func transcribeAudio(filePath string) (string, error) {
-    fmt.Println("Transcribing audio...")
-    cmd := exec.Command("./venv/bin/python", "transcribe.py", filePath)
-    
-    // Capture both stdout and stderr separately
-    var stdout, stderr bytes.Buffer
-    cmd.Stdout = &stdout
-    cmd.Stderr = &stderr
-
-    err := cmd.Run()
-    if err != nil {
-        return "", fmt.Errorf("transcription error: %v\nPython Error: %s", 
-            err, stderr.String())
-    }
-    
-    return stdout.String(), nil
+// TranscribeOptions selects the Whisper model and spoken language. Empty
+// fields use the model default and automatic language detection.
+type TranscribeOptions struct {
+	Model    string
+	Language string
+}
+
+// transcribeAudio runs the Python transcription script. Its diagnostic
+// output goes to the job log.
+func transcribeAudio(logger *slog.Logger, filePath string, opts TranscribeOptions) (string, error) {
+	logger.Info("Transcribing audio", "model", opts.Model, "language", opts.Language)
+	args := []string{"transcribe.py", filePath}
+	if opts.Model != "" {
+		args = append(args, "--model", opts.Model)
+	}
+	if opts.Language != "" {
+		args = append(args, "--language", opts.Language)
+	}
+	cmd := exec.Command(pythonPath, args...)
+
+	// Capture both stdout and stderr separately
+	var stdout, stderr bytes.Buffer
+	output := newOutputLogger(logger, "python")
+	cmd.Stdout = &stdout
+	cmd.Stderr = io.MultiWriter(&stderr, output)
+
+	err := cmd.Run()
+	output.Flush()
+	if err != nil {
+		return "", fmt.Errorf("transcription error: %v\nPython Error: %s",
+			err, stderr.String())
+	}
+
+	return stdout.String(), nil
 }
 
 // isElementVisible checks if a Playwright locator is visible
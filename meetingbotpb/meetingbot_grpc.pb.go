@@ -0,0 +1,255 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: meetingbot/v1/meetingbot.proto
+
+// The gRPC counterpart of the HTTP API. Both transports share one scheduler
+// and job store, so a meeting started over one is visible over the other.
+// Status and event type strings match the HTTP API.
+
+package meetingbotpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MeetingBot_StartMeeting_FullMethodName  = "/meetingbot.v1.MeetingBot/StartMeeting"
+	MeetingBot_GetMeeting_FullMethodName    = "/meetingbot.v1.MeetingBot/GetMeeting"
+	MeetingBot_CancelMeeting_FullMethodName = "/meetingbot.v1.MeetingBot/CancelMeeting"
+	MeetingBot_WatchEvents_FullMethodName   = "/meetingbot.v1.MeetingBot/WatchEvents"
+)
+
+// MeetingBotClient is the client API for MeetingBot service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MeetingBotClient interface {
+	// StartMeeting sends a bot into a meeting, or schedules it when start_at is
+	// set. Send an "idempotency-key" metadata entry to make retries safe.
+	StartMeeting(ctx context.Context, in *StartMeetingRequest, opts ...grpc.CallOption) (*StartMeetingResponse, error)
+	// GetMeeting returns the state of a job
+	GetMeeting(ctx context.Context, in *GetMeetingRequest, opts ...grpc.CallOption) (*Meeting, error)
+	// CancelMeeting cancels a job or pulls its bot out of the meeting
+	CancelMeeting(ctx context.Context, in *CancelMeetingRequest, opts ...grpc.CallOption) (*Meeting, error)
+	// WatchEvents replays a job's events after after_event_id, then streams
+	// new ones until the job finishes
+	WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error)
+}
+
+type meetingBotClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMeetingBotClient(cc grpc.ClientConnInterface) MeetingBotClient {
+	return &meetingBotClient{cc}
+}
+
+func (c *meetingBotClient) StartMeeting(ctx context.Context, in *StartMeetingRequest, opts ...grpc.CallOption) (*StartMeetingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartMeetingResponse)
+	err := c.cc.Invoke(ctx, MeetingBot_StartMeeting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meetingBotClient) GetMeeting(ctx context.Context, in *GetMeetingRequest, opts ...grpc.CallOption) (*Meeting, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Meeting)
+	err := c.cc.Invoke(ctx, MeetingBot_GetMeeting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meetingBotClient) CancelMeeting(ctx context.Context, in *CancelMeetingRequest, opts ...grpc.CallOption) (*Meeting, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Meeting)
+	err := c.cc.Invoke(ctx, MeetingBot_CancelMeeting_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *meetingBotClient) WatchEvents(ctx context.Context, in *WatchEventsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Event], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MeetingBot_ServiceDesc.Streams[0], MeetingBot_WatchEvents_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchEventsRequest, Event]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MeetingBot_WatchEventsClient = grpc.ServerStreamingClient[Event]
+
+// MeetingBotServer is the server API for MeetingBot service.
+// All implementations must embed UnimplementedMeetingBotServer
+// for forward compatibility.
+type MeetingBotServer interface {
+	// StartMeeting sends a bot into a meeting, or schedules it when start_at is
+	// set. Send an "idempotency-key" metadata entry to make retries safe.
+	StartMeeting(context.Context, *StartMeetingRequest) (*StartMeetingResponse, error)
+	// GetMeeting returns the state of a job
+	GetMeeting(context.Context, *GetMeetingRequest) (*Meeting, error)
+	// CancelMeeting cancels a job or pulls its bot out of the meeting
+	CancelMeeting(context.Context, *CancelMeetingRequest) (*Meeting, error)
+	// WatchEvents replays a job's events after after_event_id, then streams
+	// new ones until the job finishes
+	WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error
+	mustEmbedUnimplementedMeetingBotServer()
+}
+
+// UnimplementedMeetingBotServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMeetingBotServer struct{}
+
+func (UnimplementedMeetingBotServer) StartMeeting(context.Context, *StartMeetingRequest) (*StartMeetingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartMeeting not implemented")
+}
+func (UnimplementedMeetingBotServer) GetMeeting(context.Context, *GetMeetingRequest) (*Meeting, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMeeting not implemented")
+}
+func (UnimplementedMeetingBotServer) CancelMeeting(context.Context, *CancelMeetingRequest) (*Meeting, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelMeeting not implemented")
+}
+func (UnimplementedMeetingBotServer) WatchEvents(*WatchEventsRequest, grpc.ServerStreamingServer[Event]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEvents not implemented")
+}
+func (UnimplementedMeetingBotServer) mustEmbedUnimplementedMeetingBotServer() {}
+func (UnimplementedMeetingBotServer) testEmbeddedByValue()                    {}
+
+// UnsafeMeetingBotServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MeetingBotServer will
+// result in compilation errors.
+type UnsafeMeetingBotServer interface {
+	mustEmbedUnimplementedMeetingBotServer()
+}
+
+func RegisterMeetingBotServer(s grpc.ServiceRegistrar, srv MeetingBotServer) {
+	// If the following call pancis, it indicates UnimplementedMeetingBotServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MeetingBot_ServiceDesc, srv)
+}
+
+func _MeetingBot_StartMeeting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartMeetingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeetingBotServer).StartMeeting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeetingBot_StartMeeting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeetingBotServer).StartMeeting(ctx, req.(*StartMeetingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeetingBot_GetMeeting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMeetingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeetingBotServer).GetMeeting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeetingBot_GetMeeting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeetingBotServer).GetMeeting(ctx, req.(*GetMeetingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeetingBot_CancelMeeting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelMeetingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MeetingBotServer).CancelMeeting(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MeetingBot_CancelMeeting_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MeetingBotServer).CancelMeeting(ctx, req.(*CancelMeetingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MeetingBot_WatchEvents_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MeetingBotServer).WatchEvents(m, &grpc.GenericServerStream[WatchEventsRequest, Event]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MeetingBot_WatchEventsServer = grpc.ServerStreamingServer[Event]
+
+// MeetingBot_ServiceDesc is the grpc.ServiceDesc for MeetingBot service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MeetingBot_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "meetingbot.v1.MeetingBot",
+	HandlerType: (*MeetingBotServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "StartMeeting",
+			Handler:    _MeetingBot_StartMeeting_Handler,
+		},
+		{
+			MethodName: "GetMeeting",
+			Handler:    _MeetingBot_GetMeeting_Handler,
+		},
+		{
+			MethodName: "CancelMeeting",
+			Handler:    _MeetingBot_CancelMeeting_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEvents",
+			Handler:       _MeetingBot_WatchEvents_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "meetingbot/v1/meetingbot.proto",
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Join failure reasons that do not depend on how far the bot got
+const (
+	joinReasonCancelled    = "cancelled"
+	joinReasonDenied       = "denied"
+	joinReasonLobbyTimeout = "lobby_timeout"
+)
+
+// Steps of joining a meeting, used as the failure reason when a step fails
+const (
+	joinStepAudio    = "audio_setup"
+	joinStepBrowser  = "browser_launch"
+	joinStepNavigate = "navigation"
+	joinStepJoin     = "join"
+)
+
+// Recording sources
+const (
+	sourceBot    = "bot"
+	sourceUpload = "upload"
+)
+
+var (
+	errJoinDenied   = errors.New("request to join the meeting was denied")
+	errLobbyTimeout = errors.New("not admitted from the lobby")
+)
+
+var (
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "meetai_bots_active",
+		Help: "Meeting bots currently running.",
+	}, func() float64 {
+		running, _ := scheduler.Stats()
+		return float64(running)
+	})
+	_ = promauto.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "meetai_jobs_queued",
+		Help: "Jobs waiting for a free bot slot.",
+	}, func() float64 {
+		_, queued := scheduler.Stats()
+		return float64(queued)
+	})
+
+	meetingJoins = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "meetai_meeting_joins_total",
+		Help: "Attempts to join a meeting by result and reason.",
+	}, []string{"result", "reason"})
+	lobbyWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "meetai_lobby_wait_seconds",
+		Help:    "Time spent waiting to be admitted from the lobby, by outcome.",
+		Buckets: []float64{5, 15, 30, 60, 120, 300, 600},
+	}, []string{"outcome"})
+	meetingDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "meetai_meeting_duration_seconds",
+		Help:    "Time the bot spent recording a meeting.",
+		Buckets: []float64{60, 300, 900, 1800, 3600, 5400, 7200, 14400},
+	})
+	recordingBytes = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "meetai_recording_bytes",
+		Help:    "Size of recordings entering the pipeline, by source.",
+		Buckets: prometheus.ExponentialBuckets(1<<20, 4, 8),
+	}, []string{"source"})
+	transcriptionLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "meetai_transcription_duration_seconds",
+		Help:    "Time taken to transcribe a recording with Whisper.",
+		Buckets: prometheus.ExponentialBuckets(5, 2, 10),
+	})
+	summarizationLatency = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "meetai_summarization_duration_seconds",
+		Help:    "Time taken to summarize a transcript with Ollama.",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+	})
+	whisperErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "meetai_whisper_errors_total",
+		Help: "Transcriptions that failed.",
+	})
+	ollamaErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "meetai_ollama_errors_total",
+		Help: "Summarizations that failed.",
+	})
+)
+
+var metricsHandler = promhttp.Handler()
+
+// handleMetrics serves the metrics in the Prometheus text format
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	metricsHandler.ServeHTTP(w, r)
+}
+
+// recordJoinSuccess counts a join, through the lobby or directly
+func recordJoinSuccess(viaLobby bool) {
+	reason := "direct"
+	if viaLobby {
+		reason = "admitted"
+	}
+	meetingJoins.WithLabelValues("success", reason).Inc()
+}
+
+// recordJoinFailure counts a join that failed at step
+func recordJoinFailure(step string, err error) {
+	meetingJoins.WithLabelValues("failure", joinFailureReason(step, err)).Inc()
+}
+
+// joinFailureReason explains why a join failed, falling back to the step
+// it failed at
+func joinFailureReason(step string, err error) string {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return joinReasonCancelled
+	case errors.Is(err, errJoinDenied):
+		return joinReasonDenied
+	case errors.Is(err, errLobbyTimeout):
+		return joinReasonLobbyTimeout
+	}
+	return step
+}
+
+// recordLobbyWait records how long the bot waited in the lobby and how it ended
+func recordLobbyWait(start time.Time, err error) {
+	outcome := "admitted"
+	if err != nil {
+		outcome = joinFailureReason("error", err)
+	}
+	lobbyWait.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+}
+
+// recordRecordingSize records the size of a recording file
+func recordRecordingSize(source, path string) {
+	if info, err := os.Stat(path); err == nil {
+		recordingBytes.WithLabelValues(source).Observe(float64(info.Size()))
+	}
+}
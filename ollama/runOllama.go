@@ -1,25 +1,63 @@
 package ollama
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"regexp"
 	"strings"
 )
 
+const (
+	// DefaultModel is the Ollama model used when none is chosen
+	DefaultModel = "llama3.2"
+	// DefaultPrompt is the instruction placed before the transcript
+	DefaultPrompt = "Strictly summarize this in 100 words"
+)
+
+// Options selects the model and prompt for a summary. Empty fields use the defaults.
+type Options struct {
+	Model  string
+	Prompt string
+	// Output, if set, receives ollama's diagnostic output
+	Output io.Writer
+}
+
 func RunOllama(transcribe string) (string, error) {
-	fmt.Println("Summarizing with Ollama...")
-	cmd := exec.Command("ollama", "run", "llama3.2", "Strictly summarize this in 100 words \n"+transcribe)
+	return Summarize(transcribe, Options{})
+}
+
+// Summarize runs the transcript through an Ollama model with the given prompt
+func Summarize(transcribe string, opts Options) (string, error) {
+	if opts.Model == "" {
+		opts.Model = DefaultModel
+	}
+	if opts.Prompt == "" {
+		opts.Prompt = DefaultPrompt
+	}
+
+	cmd := exec.Command("ollama", "run", opts.Model, opts.Prompt+" \n"+transcribe)
 	cmd.Env = os.Environ()
 
-	output, err := cmd.CombinedOutput()
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	diagnostics := cleanANSI(stderr.String())
+	if opts.Output != nil && diagnostics != "" {
+		fmt.Fprintln(opts.Output, diagnostics)
+	}
 	if err != nil {
-
+		if diagnostics != "" {
+			err = fmt.Errorf("%v: %s", err, diagnostics)
+		}
 		return "", fmt.Errorf("error running ollama: %v", err)
 	}
 
-	cleanOutput := cleanANSI(string(output))
+	cleanOutput := cleanANSI(stdout.String())
 	// fmt.Println("Output: ", cleanOutput)
 	return cleanOutput, nil
 }
@@ -32,3 +70,21 @@ func cleanANSI(input string) string {
 	cleaned = thinkTagRegex.ReplaceAllString(cleaned, "")
 	return strings.TrimSpace(cleaned)
 }
+
+// CheckModel verifies that the ollama binary works and the model has been pulled
+func CheckModel(ctx context.Context, model string) error {
+	output, err := exec.CommandContext(ctx, "ollama", "list").CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("ollama list failed: %v: %s", err, strings.TrimSpace(string(output)))
+	}
+	for _, line := range strings.Split(string(output), "\n")[1:] {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if name := fields[0]; name == model || name == model+":latest" {
+			return nil
+		}
+	}
+	return fmt.Errorf("model %s is not pulled, run \"ollama pull %s\"", model, model)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec documents every HTTP endpoint. It is embedded so the served
+// spec always matches the binary.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// route is one HTTP endpoint served by the API
+type route struct {
+	method  string
+	path    string
+	handler http.HandlerFunc
+}
+
+// routes lists every endpoint. Adding one here without documenting it in
+// openapi.json, or the other way round, fails TestSpecDocumentsEveryRoute.
+var routes = []route{
+	{"POST", "/start-meeting", handleStartMeeting},
+	{"GET", "/meetings", handleListMeetings},
+	{"GET", "/meetings/{id}", handleGetMeeting},
+	{"PATCH", "/meetings/{id}", handleRescheduleMeeting},
+	{"DELETE", "/meetings/{id}", handleCancelMeeting},
+	{"GET", "/scheduled", handleListScheduled},
+	{"GET", "/meetings/{id}/events", handleMeetingEvents},
+	{"GET", "/meetings/{id}/recording", handleArtifact(ArtifactRecording)},
+	{"GET", "/meetings/{id}/transcript", handleArtifact(ArtifactTranscript)},
+	{"GET", "/meetings/{id}/summary", handleArtifact(ArtifactSummary)},
+	{"GET", "/meetings/{id}/log", handleArtifact(ArtifactLog)},
+	{"POST", "/meetings/{id}/reprocess", handleReprocessMeeting},
+	{"POST", "/recordings", handleUploadRecording},
+	{"GET", "/openapi.json", handleOpenAPISpec},
+	{"GET", "/healthz", handleHealthz},
+	{"GET", "/readyz", handleReadyz},
+	{"GET", "/metrics", handleMetrics},
+	{"GET", "/selectors", handleSelectors},
+}
+
+// registerRoutes adds every route to mux
+func registerRoutes(mux *http.ServeMux) {
+	for _, rt := range routes {
+		mux.HandleFunc(rt.method+" "+rt.path, rt.handler)
+	}
+}
+
+// handleOpenAPISpec serves the OpenAPI document
+func handleOpenAPISpec(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(openAPISpec)
+}
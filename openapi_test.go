@@ -0,0 +1,162 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestSpecDocumentsEveryRoute(t *testing.T) {
+	if err := checkSpecRoutes(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// specSchemaTypes maps every schema in openapi.json to the Go type it documents
+var specSchemaTypes = map[string]any{
+	"JobStatus":            JobStatus(""),
+	"Duration":             Duration(0),
+	"MeetingRequest":       MeetingRequest{},
+	"RescheduleRequest":    RescheduleRequest{},
+	"StartMeetingResponse": StartMeetingResponse{},
+	"StatusChange":         StatusChange{},
+	"DeliveryAttempt":      DeliveryAttempt{},
+	"Job":                  JobInfo{},
+	"MeetingStatus":        MeetingStatusResponse{},
+	"MeetingList":          MeetingListResponse{},
+	"Event":                Event{},
+	"Error":                ErrorResponse{},
+	"FieldError":           FieldError{},
+	"ValidationError":      ValidationErrorResponse{},
+	"WebhookPayload":       WebhookPayload{},
+	"ReprocessRequest":     ReprocessRequest{},
+	"ArtifactVersion":      ArtifactVersion{},
+	"UploadInfo":           UploadInfo{},
+	"CheckResult":          CheckResult{},
+	"HealthReport":         HealthReport{},
+	"SelectorHits":         SelectorHits{},
+	"SelectorPackStatus":   SelectorPackStatus{},
+}
+
+// specSchema is the part of a schema the test compares
+type specSchema struct {
+	Properties map[string]json.RawMessage `json:"properties"`
+	AllOf      []specSchema               `json:"allOf"`
+	Ref        string                     `json:"$ref"`
+}
+
+func TestSpecSchemasMatchStructs(t *testing.T) {
+	var spec struct {
+		Components struct {
+			Schemas map[string]specSchema `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		t.Fatalf("openapi.json is not valid JSON: %v", err)
+	}
+
+	for name, schema := range spec.Components.Schemas {
+		value, ok := specSchemaTypes[name]
+		if !ok {
+			t.Errorf("schema %s is not mapped to a Go type in specSchemaTypes", name)
+			continue
+		}
+		documented := schemaProperties(spec.Components.Schemas, schema)
+		var serialized []string
+		if typ := reflect.TypeOf(value); typ.Kind() == reflect.Struct {
+			serialized = jsonFieldNames(typ)
+		}
+		if !slices.Equal(documented, serialized) {
+			t.Errorf("schema %s documents %v, but %T serializes %v", name, documented, value, serialized)
+		}
+	}
+	for name := range specSchemaTypes {
+		if _, ok := spec.Components.Schemas[name]; !ok {
+			t.Errorf("specSchemaTypes maps %s, which openapi.json does not define", name)
+		}
+	}
+}
+
+// schemaProperties returns the sorted property names of a schema, following
+// $ref and merging allOf
+func schemaProperties(schemas map[string]specSchema, schema specSchema) []string {
+	var names []string
+	if ref, ok := strings.CutPrefix(schema.Ref, "#/components/schemas/"); ok {
+		names = append(names, schemaProperties(schemas, schemas[ref])...)
+	}
+	for _, part := range schema.AllOf {
+		names = append(names, schemaProperties(schemas, part)...)
+	}
+	for name := range schema.Properties {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// jsonFieldNames returns the sorted JSON names of a struct's serialized
+// fields, including those of embedded structs
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case name == "-":
+			continue
+		case name == "" && field.Anonymous && field.Type.Kind() == reflect.Struct:
+			names = append(names, jsonFieldNames(field.Type)...)
+			continue
+		case name == "":
+			name = field.Name
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// checkSpecRoutes verifies that openapi.json documents exactly the routes
+// the server registers
+func checkSpecRoutes() error {
+	var spec struct {
+		Paths map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(openAPISpec, &spec); err != nil {
+		return fmt.Errorf("openapi.json is not valid JSON: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for method := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+				documented[strings.ToUpper(method)+" "+path] = true
+			}
+		}
+	}
+
+	var problems []string
+	for _, rt := range routes {
+		key := rt.method + " " + rt.path
+		if !documented[key] {
+			problems = append(problems, key+" is served but not documented")
+		}
+		delete(documented, key)
+	}
+	for key := range documented {
+		problems = append(problems, key+" is documented but not served")
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("openapi.json is out of date: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+// MeetingPlatform hides the web client of one meeting service from the bot.
+// The platform is chosen from the meeting URL.
+type MeetingPlatform interface {
+	// Name is the human readable name of the service
+	Name() string
+	// Matches reports whether a meeting URL belongs to the platform
+	Matches(u *url.URL) bool
+	// NormalizeURL validates a matching URL and returns its canonical form,
+	// which is used to spot duplicate bots for the same meeting
+	NormalizeURL(u *url.URL) (string, error)
+	// ShowsEmails reports whether participants can be found by email, or
+	// only by display name
+	ShowsEmails() bool
+
+	// Join fills in the bot's name, and the passcode where the platform
+	// asks for one, and joins the meeting. It reports whether the bot had to
+	// ask to join and is now waiting in the lobby.
+	Join(logger *slog.Logger, page playwright.Page, botName, passcode string) (inLobby bool, err error)
+	// Admission reports whether a bot in the lobby has been let in or turned away
+	Admission(page playwright.Page) (admitted, denied bool)
+	// MeetingEnded reports whether the page shows that the bot is no longer
+	// in the meeting, and the indicator that showed it
+	MeetingEnded(page playwright.Page) (indicator string, ended bool)
+	// IsPersonInMeeting reports whether the person the bot follows is present
+	IsPersonInMeeting(logger *slog.Logger, page playwright.Page, email, name string) bool
+	// Leave exits the meeting gracefully
+	Leave(logger *slog.Logger, page playwright.Page)
+}
+
+// audioJoiner is implemented by platforms whose client only plays the
+// meeting audio once the bot connects to it explicitly
+type audioJoiner interface {
+	// JoinAudio connects to the meeting audio after the bot is admitted
+	JoinAudio(logger *slog.Logger, page playwright.Page) error
+}
+
+// localizedPlatform is implemented by platforms whose selectors follow the
+// browser's UI language
+type localizedPlatform interface {
+	// WithLocale returns the platform for a browser running in locale
+	WithLocale(locale string) MeetingPlatform
+}
+
+// platforms lists every supported meeting service
+var platforms = []MeetingPlatform{
+	googleMeet{},
+	jitsiMeet{},
+	microsoftTeams{},
+	zoomMeeting{},
+}
+
+// platformFor returns the platform a meeting URL belongs to
+func platformFor(rawURL string) (MeetingPlatform, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range platforms {
+		if p.Matches(u) {
+			return p, nil
+		}
+	}
+	return nil, fmt.Errorf("%s is not a supported meeting URL", rawURL)
+}
+
+// normalizeMeetingURL checks that rawURL points at a meeting on a supported
+// platform and returns it in the platform's canonical form
+func normalizeMeetingURL(rawURL string) (string, error) {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return "", errors.New("is required")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "https://" + rawURL
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", errors.New("is not a valid URL")
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return "", errors.New("must be an https URL")
+	}
+	for _, p := range platforms {
+		if p.Matches(u) {
+			return p.NormalizeURL(u)
+		}
+	}
+	return "", fmt.Errorf("must be a %s URL", supportedPlatforms())
+}
+
+// supportedPlatforms lists the platform names for error messages
+func supportedPlatforms() string {
+	names := make([]string, len(platforms))
+	for i, p := range platforms {
+		names[i] = p.Name()
+	}
+	if len(names) == 1 {
+		return names[0]
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
+
+// waitForAdmission polls the page until the host admits the bot from the
+// lobby, the request is denied, the timeout expires or ctx is cancelled
+func waitForAdmission(ctx context.Context, platform MeetingPlatform, page playwright.Page, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		admitted, denied := platform.Admission(page)
+		if admitted {
+			return nil
+		}
+		if denied {
+			return errJoinDenied
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+	return fmt.Errorf("%w within %v", errLobbyTimeout, timeout)
+}
+
+// firstVisible returns the first selector with a visible match on the page
+func firstVisible(page playwright.Page, selectors ...string) (string, bool) {
+	for _, selector := range selectors {
+		if isElementVisible(page.Locator(selector).First()) {
+			return selector, true
+		}
+	}
+	return "", false
+}
+
+// waitForVisible polls the page until one of the selectors has a visible
+// match or the timeout expires
+func waitForVisible(page playwright.Page, timeout time.Duration, selectors ...string) (string, bool) {
+	deadline := time.Now().Add(timeout)
+	for {
+		if selector, ok := firstVisible(page, selectors...); ok {
+			return selector, true
+		}
+		if time.Now().After(deadline) {
+			return "", false
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+// clickFirstVisible clicks the first selector with a visible match and
+// reports whether a click succeeded
+func clickFirstVisible(logger *slog.Logger, page playwright.Page, name string, selectors ...string) bool {
+	for _, selector := range selectors {
+		button := page.Locator(selector).First()
+		if !isElementVisible(button) {
+			continue
+		}
+		if err := button.Click(); err != nil {
+			logger.Warn("Could not click button", "button", name, "error", err)
+			continue
+		}
+		logger.Info("Clicked button", "button", name)
+		return true
+	}
+	return false
+}
+
+// containsText reports whether an element matching one of the selectors
+// contains text. The text is matched literally, so names with quotes are safe.
+func containsText(page playwright.Page, text string, selectors ...string) bool {
+	if text == "" {
+		return false
+	}
+	for _, selector := range selectors {
+		match := page.Locator(selector).Filter(playwright.LocatorFilterOptions{HasText: text})
+		if isElementVisible(match.First()) {
+			return true
+		}
+	}
+	return false
+}
+
+// leaveByButton clicks the first visible hang up button. The page is closed
+// by the caller either way.
+func leaveByButton(logger *slog.Logger, page playwright.Page, selectors ...string) {
+	if clickFirstVisible(logger, page, "Leave", selectors...) {
+		logger.Info("Left the meeting")
+		randomDelay(1, 2)
+		return
+	}
+	logger.Warn("Could not find leave meeting button, closing page instead")
+}
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"meetai/ollama"
+)
+
+// Pipeline stages that can be rerun
+const (
+	StageTranscribe = "transcribe"
+	StageSummarize  = "summarize"
+)
+
+const maxPromptLength = 4000
+
+var (
+	// modelNamePattern matches Whisper and Ollama model names such as
+	// "large-v3" or "llama3.1:8b"
+	modelNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]{0,99}$`)
+	// languagePattern matches Whisper language codes and names such as "de" or "german"
+	languagePattern = regexp.MustCompile(`^[A-Za-z]{2,20}$`)
+)
+
+// ReprocessRequest reruns transcription and/or summarization of a finished
+// job with optional model, language and prompt overrides
+type ReprocessRequest struct {
+	Stages             []string `json:"stages,omitempty"`
+	TranscriptionModel string   `json:"transcription_model,omitempty"`
+	Language           string   `json:"language,omitempty"`
+	SummaryModel       string   `json:"summary_model,omitempty"`
+	Prompt             string   `json:"prompt,omitempty"`
+}
+
+// Validate checks the request, defaulting to both stages when none are given
+func (r *ReprocessRequest) Validate() error {
+	verr := &ValidationError{}
+	if len(r.Stages) == 0 {
+		r.Stages = []string{StageTranscribe, StageSummarize}
+	}
+	for _, stage := range r.Stages {
+		if stage != StageTranscribe && stage != StageSummarize {
+			verr.add("stages", "unknown stage %q, expected %q or %q", stage, StageTranscribe, StageSummarize)
+		}
+	}
+
+	if r.TranscriptionModel != "" {
+		if !r.has(StageTranscribe) {
+			verr.add("transcription_model", "requires the %q stage", StageTranscribe)
+		} else if !modelNamePattern.MatchString(r.TranscriptionModel) {
+			verr.add("transcription_model", "is not a valid model name")
+		}
+	}
+	if r.Language != "" {
+		if !r.has(StageTranscribe) {
+			verr.add("language", "requires the %q stage", StageTranscribe)
+		} else if !languagePattern.MatchString(r.Language) {
+			verr.add("language", "must be a language code like \"en\" or a name like \"german\"")
+		}
+	}
+	if r.SummaryModel != "" {
+		if !r.has(StageSummarize) {
+			verr.add("summary_model", "requires the %q stage", StageSummarize)
+		} else if !modelNamePattern.MatchString(r.SummaryModel) {
+			verr.add("summary_model", "is not a valid model name")
+		}
+	}
+	if r.Prompt != "" && !r.has(StageSummarize) {
+		verr.add("prompt", "requires the %q stage", StageSummarize)
+	}
+	if utf8.RuneCountInString(r.Prompt) > maxPromptLength {
+		verr.add("prompt", "must be at most %d characters", maxPromptLength)
+	}
+	return verr.errOrNil()
+}
+
+// has reports whether a stage is to be rerun
+func (r *ReprocessRequest) has(stage string) bool {
+	for _, s := range r.Stages {
+		if s == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// StartReprocess reopens a finished job for a reprocessing run. It reports
+// false if the job is still running.
+func (j *Job) StartReprocess(req ReprocessRequest) bool {
+	status := StatusSummarizing
+	if req.has(StageTranscribe) {
+		status = StatusTranscribing
+	}
+
+	j.mu.Lock()
+	if !j.info.Status.Terminal() {
+		j.mu.Unlock()
+		return false
+	}
+	now := time.Now()
+	j.info.Reprocess = &req
+	j.info.Status = status
+	j.info.Error = ""
+	j.info.FinishedAt = nil
+	j.info.UpdatedAt = now
+	j.info.History = append(j.info.History, StatusChange{Status: status, At: now})
+	j.saveLocked()
+	j.mu.Unlock()
+
+	if j.store != nil && j.store.events != nil {
+		j.store.events.Reopen(j.ID())
+	}
+	j.publishStatus(status, "")
+	return true
+}
+
+// PendingReprocess returns the reprocessing run in progress, if any
+func (j *Job) PendingReprocess() *ReprocessRequest {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.info.Reprocess == nil {
+		return nil
+	}
+	req := *j.info.Reprocess
+	return &req
+}
+
+// clearReprocess marks the reprocessing run as finished
+func (j *Job) clearReprocess() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.info.Reprocess = nil
+	j.saveLocked()
+}
+
+// handleReprocessMeeting reruns transcription and/or summarization of a
+// finished job. New outputs are stored as new versions. Only the job's
+// owner and admin keys may reprocess it, not keys that attached to it.
+func handleReprocessMeeting(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobForRequest(w, r)
+	if !ok {
+		return
+	}
+	if !ownsJob(apiKeyFrom(r), job) {
+		writeError(w, http.StatusForbidden, "Only the key that started the meeting can reprocess it")
+		return
+	}
+
+	var req ReprocessRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	if !job.Status().Terminal() {
+		writeError(w, http.StatusConflict, "Meeting job is still running")
+		return
+	}
+	if req.has(StageTranscribe) {
+		path, ok := job.Artifact(ArtifactRecording)
+		if _, err := os.Stat(path); !ok || err != nil {
+			writeError(w, http.StatusConflict, "The recording of this meeting is not available")
+			return
+		}
+	} else if _, ok := readArtifact(job, ArtifactTranscript); !ok {
+		writeError(w, http.StatusConflict, "This meeting has no transcript to summarize")
+		return
+	}
+
+	if !reserveProcessing(w) {
+		return
+	}
+	if !job.StartReprocess(req) {
+		scheduler.Release()
+		writeError(w, http.StatusConflict, "Meeting job is still running")
+		return
+	}
+	scheduler.RunReserved(func() { runReprocess(job) })
+
+	writeJSON(w, http.StatusAccepted, MeetingStatusResponse{JobInfo: job.InfoFor(apiKeyFrom(r))})
+}
+
+// runReprocess runs a reprocessing job and reports the result like a first run
+func runReprocess(job *Job) {
+	if err := reprocessRecording(job); err != nil {
+		job.clearReprocess()
+		job.Fail(err)
+	}
+	notifyCompletion(job)
+}
+
+// reprocessRecording reruns the stages chosen in the job's pending
+// reprocessing request
+func reprocessRecording(job *Job) error {
+	req := job.PendingReprocess()
+	if req == nil {
+		return errors.New("no reprocessing request found")
+	}
+
+	var transcript string
+	if req.has(StageTranscribe) {
+		audioFilePath, ok := job.Artifact(ArtifactRecording)
+		if !ok {
+			return errors.New("the recording is no longer available")
+		}
+		var err error
+		transcript, err = transcribeStage(job, audioFilePath, TranscribeOptions{
+			Model:    req.TranscriptionModel,
+			Language: req.Language,
+		})
+		if err != nil {
+			return err
+		}
+	} else {
+		var ok bool
+		if transcript, ok = readArtifact(job, ArtifactTranscript); !ok {
+			return errors.New("no transcript to summarize")
+		}
+	}
+
+	if req.has(StageSummarize) {
+		// Summaries are named after the recording, or after the transcript
+		// if the recording was discarded
+		audioFilePath, _ := job.Artifact(ArtifactRecording)
+		if audioFilePath == "" {
+			audioFilePath, _ = job.Artifact(ArtifactTranscript)
+		}
+		err := summarizeStage(job, audioFilePath, transcript, ollama.Options{
+			Model:  req.SummaryModel,
+			Prompt: req.Prompt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	job.clearReprocess()
+	job.SetStatus(StatusDelivered)
+	return nil
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestOnlyOwnerCanReprocess(t *testing.T) {
+	owner, subscriber := &APIKey{ID: "owner-team"}, &APIKey{ID: "other-team"}
+	withKeys(t, map[string]*APIKey{"owner-token": owner, "other-token": subscriber})
+
+	job := jobs.Create(MeetingRequest{MeetingURL: "https://meet.google.com/abc-defg-hij"}, owner.ID)
+	t.Cleanup(func() { jobs.Delete(job.ID()) })
+	job.Attach(Subscriber{APIKeyID: subscriber.ID})
+	job.SetStatus(StatusDelivered)
+
+	mux := http.NewServeMux()
+	registerRoutes(mux)
+	tests := []struct {
+		token string
+		want  int
+	}{
+		{"other-token", http.StatusForbidden},
+		// The owner gets past the check to the missing transcript
+		{"owner-token", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/meetings/"+job.ID()+"/reprocess", strings.NewReader(`{"stages":["summarize"]}`))
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		requireAPIKey(mux).ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: reprocess = %d %s, want %d", tt.token, rec.Code, rec.Body, tt.want)
+		}
+	}
+}
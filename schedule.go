@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Duration is a time.Duration that reads from JSON as either a Go duration
+// string ("5m", "90s") or a number of seconds
+type Duration time.Duration
+
+// MarshalJSON encodes the duration as a Go duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// UnmarshalJSON accepts a duration string or a number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("duration must be a string like \"5m\" or a number of seconds")
+	}
+	parsed, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// LaunchAt returns when the bot for a request should start, or the zero
+// time if the request is not scheduled
+func (r MeetingRequest) LaunchAt() time.Time {
+	if r.StartAt == nil {
+		return time.Time{}
+	}
+	return r.StartAt.Add(-time.Duration(r.JoinEarly))
+}
+
+// Schedule holds a job until its launch time and then submits it. Scheduled
+// jobs are not subject to the queue limit when they fire, so a planned
+// meeting is never dropped because of a burst of ad-hoc requests.
+func (s *Scheduler) Schedule(job *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrShuttingDown
+	}
+	s.scheduleLocked(job)
+	return nil
+}
+
+// scheduledJob is a job waiting for its launch timer
+type scheduledJob struct {
+	job   *Job
+	timer *time.Timer
+}
+
+func (s *Scheduler) scheduleLocked(job *Job) {
+	entry := &scheduledJob{job: job}
+	s.scheduled[job.ID()] = entry
+	entry.timer = time.AfterFunc(time.Until(job.Request().LaunchAt()), func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.scheduled[job.ID()] != entry {
+			return
+		}
+		delete(s.scheduled, job.ID())
+
+		job.Logger().Info("Scheduled join is due, queueing bot")
+		job.SetStatus(StatusQueued)
+		s.queue = append(s.queue, job)
+		s.dispatchLocked()
+	})
+}
+
+// Reschedule moves a pending scheduled job to a new start time. It reports
+// false if the job is no longer waiting for its launch time.
+func (s *Scheduler) Reschedule(job *Job, startAt time.Time, joinEarly Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.scheduled[job.ID()]
+	if !ok {
+		return false
+	}
+	entry.timer.Stop()
+	job.SetSchedule(startAt, joinEarly)
+	s.scheduleLocked(job)
+	return true
+}
+
+// Scheduled returns the jobs waiting for their launch time, soonest first
+func (s *Scheduler) Scheduled() []*Job {
+	s.mu.Lock()
+	pending := make([]*Job, 0, len(s.scheduled))
+	for _, entry := range s.scheduled {
+		pending = append(pending, entry.job)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Request().LaunchAt().Before(pending[j].Request().LaunchAt())
+	})
+	return pending
+}
+
+// cancelScheduledLocked drops a pending scheduled job
+func (s *Scheduler) cancelScheduledLocked(job *Job) bool {
+	entry, ok := s.scheduled[job.ID()]
+	if !ok {
+		return false
+	}
+	entry.timer.Stop()
+	delete(s.scheduled, job.ID())
+	return true
+}
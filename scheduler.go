@@ -0,0 +1,287 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+var (
+	// ErrQueueFull is returned when every bot slot is busy and the wait queue is at capacity
+	ErrQueueFull = errors.New("meeting bot queue is full")
+	// ErrShuttingDown is returned once the scheduler no longer accepts jobs
+	ErrShuttingDown = errors.New("meeting bot service is shutting down")
+	// ErrProcessingBusy is returned when every post-processing slot is taken
+	ErrProcessingBusy = errors.New("every post-processing slot is busy")
+	// ErrJobFinished is returned when cancelling a job that is already over
+	ErrJobFinished = errors.New("meeting job has already finished")
+	// ErrJobProcessing is returned when cancelling a job whose recording is
+	// already being transcribed or summarized
+	ErrJobProcessing = errors.New("meeting has already been recorded and is being processed")
+)
+
+// Scheduler owns the process-wide bot slots. Jobs beyond the concurrency
+// limit wait in a FIFO queue until a slot frees up. Post-processing that
+// runs without a bot, such as uploads and reprocessing, has its own slots
+// so that Whisper and Ollama runs are bounded too.
+type Scheduler struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	maxQueued     int
+	running       int
+	queue         []*Job
+	scheduled     map[string]*scheduledJob
+	cancels       map[string]context.CancelFunc
+	run           func(context.Context, *Job) error
+	closed        bool
+	wg            sync.WaitGroup
+	// processing holds one token per post-processing slot in use
+	processing chan struct{}
+}
+
+// NewScheduler creates a scheduler that runs at most maxConcurrent jobs at
+// once, holds at most maxQueued jobs waiting for a slot and runs at most
+// maxProcessing post-processing tasks at once
+func NewScheduler(maxConcurrent, maxQueued, maxProcessing int, run func(context.Context, *Job) error) *Scheduler {
+	if maxConcurrent < 1 {
+		maxConcurrent = 1
+	}
+	if maxProcessing < 1 {
+		maxProcessing = 1
+	}
+	if maxQueued < 0 {
+		maxQueued = 0
+	}
+	return &Scheduler{
+		maxConcurrent: maxConcurrent,
+		maxQueued:     maxQueued,
+		scheduled:     make(map[string]*scheduledJob),
+		cancels:       make(map[string]context.CancelFunc),
+		run:           run,
+		processing:    make(chan struct{}, maxProcessing),
+	}
+}
+
+// Submit hands a job to the scheduler. It returns the job's 1-based position
+// in the wait queue, or 0 if the job got a slot straight away.
+func (s *Scheduler) Submit(job *Job) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return 0, ErrShuttingDown
+	}
+	if s.running < s.maxConcurrent && len(s.queue) == 0 {
+		s.startLocked(job)
+		return 0, nil
+	}
+	if len(s.queue) >= s.maxQueued {
+		return 0, ErrQueueFull
+	}
+	s.queue = append(s.queue, job)
+	return len(s.queue), nil
+}
+
+// Enqueue appends a job to the wait queue regardless of the queue limit.
+// It is used for jobs the service already accepted, such as scheduled or
+// restored ones.
+func (s *Scheduler) Enqueue(job *Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	job.SetStatus(StatusQueued)
+	s.queue = append(s.queue, job)
+	s.dispatchLocked()
+}
+
+// Position returns the 1-based queue position of a job, or 0 if it is not waiting
+func (s *Scheduler) Position(jobID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, job := range s.queue {
+		if job.ID() == jobID {
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// Cancel stops a job. Scheduled and queued jobs are dropped, a running job
+// has its context cancelled so the bot leaves the meeting. It returns
+// ErrJobProcessing once the recording is being processed and ErrJobFinished
+// if the job is over or the scheduler does not know it.
+func (s *Scheduler) Cancel(job *Job, discardRecording bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cancelScheduledLocked(job) {
+		job.SetStatus(StatusCancelled)
+		notifyCompletion(job)
+		return nil
+	}
+
+	for i, queued := range s.queue {
+		if queued.ID() == job.ID() {
+			s.queue = append(s.queue[:i], s.queue[i+1:]...)
+			job.SetStatus(StatusCancelled)
+			notifyCompletion(job)
+			return nil
+		}
+	}
+
+	cancel, ok := s.cancels[job.ID()]
+	if !ok {
+		if job.Status().Processing() {
+			return ErrJobProcessing
+		}
+		return ErrJobFinished
+	}
+	return job.cancelBot(cancel, discardRecording)
+}
+
+// Go runs post-processing the service already accepted, such as work
+// resumed after a restart, once a post-processing slot is free. Shutdown
+// waits for it.
+func (s *Scheduler) Go(fn func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrShuttingDown
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.processing <- struct{}{}
+		defer func() { <-s.processing }()
+		fn()
+	}()
+	return nil
+}
+
+// Reserve takes a post-processing slot for a new request without waiting.
+// The slot is handed to RunReserved, or given back with Release if the
+// request fails before any work starts.
+func (s *Scheduler) Reserve() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return ErrShuttingDown
+	}
+	select {
+	case s.processing <- struct{}{}:
+		s.wg.Add(1)
+		return nil
+	default:
+		return ErrProcessingBusy
+	}
+}
+
+// RunReserved runs fn in a slot taken with Reserve and frees it when fn returns
+func (s *Scheduler) RunReserved(fn func()) {
+	go func() {
+		defer s.Release()
+		fn()
+	}()
+}
+
+// Release gives back a slot taken with Reserve
+func (s *Scheduler) Release() {
+	<-s.processing
+	s.wg.Done()
+}
+
+// Shutdown stops accepting jobs and waits for running bots and their
+// post-processing to finish. With leave set, or once ctx expires, bots still
+// in a meeting are told to leave and their recordings are processed.
+// Scheduled and queued jobs stay in the job store and resume on next start.
+func (s *Scheduler) Shutdown(ctx context.Context, leave bool) {
+	s.mu.Lock()
+	s.closed = true
+	for id, entry := range s.scheduled {
+		entry.timer.Stop()
+		delete(s.scheduled, id)
+	}
+	s.queue = nil
+	if leave {
+		s.cancelAllLocked()
+	}
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return
+	case <-ctx.Done():
+		slog.Warn("Shutdown deadline reached, asking remaining bots to leave")
+	}
+
+	s.mu.Lock()
+	s.cancelAllLocked()
+	s.mu.Unlock()
+	<-done
+}
+
+func (s *Scheduler) cancelAllLocked() {
+	for _, cancel := range s.cancels {
+		cancel()
+	}
+}
+
+// Stats returns the number of running and queued jobs
+func (s *Scheduler) Stats() (running, queued int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.running, len(s.queue)
+}
+
+// Accepting reports whether the scheduler still takes new jobs
+func (s *Scheduler) Accepting() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !s.closed
+}
+
+// startLocked runs a job in its own goroutine and frees the slot when it is done
+func (s *Scheduler) startLocked(job *Job) {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancels[job.ID()] = cancel
+	s.running++
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer cancel()
+		if err := s.run(ctx, job); err != nil {
+			if errors.Is(err, context.Canceled) {
+				job.Logger().Info("Meeting bot cancelled")
+				job.SetStatus(StatusCancelled)
+			} else {
+				job.Logger().Error("Meeting bot error", "error", err)
+				job.Fail(err)
+			}
+		}
+		notifyCompletion(job)
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		delete(s.cancels, job.ID())
+		s.running--
+		s.dispatchLocked()
+	}()
+}
+
+// dispatchLocked starts queued jobs while there are free slots
+func (s *Scheduler) dispatchLocked() {
+	for s.running < s.maxConcurrent && len(s.queue) > 0 {
+		job := s.queue[0]
+		s.queue = s.queue[1:]
+		s.startLocked(job)
+	}
+}
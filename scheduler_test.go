@@ -0,0 +1,41 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestCancelRunningJob(t *testing.T) {
+	tests := []struct {
+		status    JobStatus
+		want      error
+		cancelled bool
+	}{
+		{StatusRecording, nil, true},
+		{StatusTranscribing, ErrJobProcessing, false},
+		{StatusSummarizing, ErrJobProcessing, false},
+		{StatusDelivered, ErrJobFinished, false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			s := NewScheduler(1, 0, 1, nil)
+			job := jobs.Create(MeetingRequest{MeetingURL: "https://meet.google.com/abc-defg-hij"}, "")
+			t.Cleanup(func() { jobs.Delete(job.ID()) })
+			job.SetStatus(tt.status)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			s.cancels[job.ID()] = cancel
+
+			if err := s.Cancel(job, true); !errors.Is(err, tt.want) {
+				t.Errorf("Cancel = %v, want %v", err, tt.want)
+			}
+			if cancelled := ctx.Err() != nil; cancelled != tt.cancelled {
+				t.Errorf("bot cancelled = %v, want %v", cancelled, tt.cancelled)
+			}
+			if job.DiscardRecording() != tt.cancelled {
+				t.Errorf("DiscardRecording = %v, want %v", job.DiscardRecording(), tt.cancelled)
+			}
+		})
+	}
+}
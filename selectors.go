@@ -0,0 +1,383 @@
+package main
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// selectorReloadInterval is how often the selector pack file is checked for changes
+const selectorReloadInterval = 10 * time.Second
+
+// builtinSelectors is the selector pack compiled into the binary. A pack
+// loaded with -selectors-file overrides it key by key.
+//
+//go:embed selectors.json
+var builtinSelectors []byte
+
+// defaultLocale is the UI language of the default chains and the browser
+// locale of requests that do not ask for one
+const defaultLocale = "en-US"
+
+// SelectorPack maps each UI element the bot looks for to a fallback chain of
+// selectors, tried in order until one matches. The default chains hold
+// English and language-independent selectors; Locales adds chains for other
+// UI languages, keyed by lower-case language tag such as "de" or "pt-br".
+type SelectorPack struct {
+	Version   string                         `json:"version"`
+	Selectors map[string][]string            `json:"selectors"`
+	Locales   map[string]map[string][]string `json:"locales,omitempty"`
+}
+
+// SelectorHits is one selector in a chain and how often it matched
+type SelectorHits struct {
+	Selector string `json:"selector"`
+	Hits     uint64 `json:"hits"`
+}
+
+// SelectorPackStatus describes the active selector pack for GET /selectors
+type SelectorPackStatus struct {
+	Version  string    `json:"version"`
+	Source   string    `json:"source"`
+	LoadedAt time.Time `json:"loaded_at"`
+	// Selectors lists each chain in order with its hit counts since startup
+	Selectors map[string][]SelectorHits            `json:"selectors"`
+	Locales   map[string]map[string][]SelectorHits `json:"locales,omitempty"`
+}
+
+var selectorHits = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "meetai_selector_hits_total",
+	Help: "Times a selector was the first in its fallback chain to match, by chain key and selector.",
+}, []string{"key", "selector"})
+
+// selectorSet holds the active selector pack and counts which selectors match
+type selectorSet struct {
+	mu       sync.RWMutex
+	builtin  SelectorPack
+	pack     SelectorPack
+	source   string
+	loadedAt time.Time
+	modTime  time.Time
+	// hits is keyed by chain key and selector, and survives reloads
+	hits map[[2]string]uint64
+}
+
+// uiSelectors is the selector pack every platform adapter reads from
+var uiSelectors = newSelectorSet()
+
+func newSelectorSet() *selectorSet {
+	var builtin SelectorPack
+	if err := decodeSelectorPack(builtinSelectors, &builtin); err != nil {
+		panic("selectors.json: " + err.Error())
+	}
+	return &selectorSet{
+		builtin:  builtin,
+		pack:     builtin,
+		source:   "builtin",
+		loadedAt: time.Now(),
+		hits:     make(map[[2]string]uint64),
+	}
+}
+
+// decodeSelectorPack parses a pack and checks that every chain has at least
+// one non-empty selector
+func decodeSelectorPack(data []byte, pack *SelectorPack) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(pack); err != nil {
+		return err
+	}
+	if strings.TrimSpace(pack.Version) == "" {
+		return errors.New("version is required")
+	}
+	if err := checkChains(pack.Selectors); err != nil {
+		return err
+	}
+	for locale, chains := range pack.Locales {
+		if locale != strings.ToLower(locale) || !localePattern.MatchString(locale) {
+			return fmt.Errorf("locale %q must be a lower-case language tag like de or pt-br", locale)
+		}
+		if err := checkChains(chains); err != nil {
+			return fmt.Errorf("locale %s: %v", locale, err)
+		}
+	}
+	return nil
+}
+
+func checkChains(chains map[string][]string) error {
+	for key, chain := range chains {
+		if len(chain) == 0 {
+			return fmt.Errorf("%s has no selectors", key)
+		}
+		for _, selector := range chain {
+			if strings.TrimSpace(selector) == "" {
+				return fmt.Errorf("%s has an empty selector", key)
+			}
+		}
+	}
+	return nil
+}
+
+// Load replaces the active pack with the one in path. Keys the file leaves
+// out keep their built-in chain, in the default set and in each locale;
+// unknown keys are rejected as they are most likely typos.
+func (s *selectorSet) Load(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	var loaded SelectorPack
+	if err := decodeSelectorPack(data, &loaded); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+
+	merged := SelectorPack{Version: loaded.Version, Locales: make(map[string]map[string][]string)}
+	if merged.Selectors, err = s.mergeChains(s.builtin.Selectors, loaded.Selectors); err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	for locale, chains := range s.builtin.Locales {
+		merged.Locales[locale] = chains
+	}
+	for locale, chains := range loaded.Locales {
+		if merged.Locales[locale], err = s.mergeChains(s.builtin.Locales[locale], chains); err != nil {
+			return fmt.Errorf("%s: locale %s: %v", path, locale, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pack = merged
+	s.source = path
+	s.loadedAt = time.Now()
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// mergeChains overrides base with the chains in override, which may only
+// use keys the built-in pack knows
+func (s *selectorSet) mergeChains(base, override map[string][]string) (map[string][]string, error) {
+	merged := make(map[string][]string, len(base)+len(override))
+	for key, chain := range base {
+		merged[key] = chain
+	}
+	for key, chain := range override {
+		if _, ok := s.builtin.Selectors[key]; !ok {
+			return nil, fmt.Errorf("unknown selector key %q", key)
+		}
+		merged[key] = chain
+	}
+	return merged, nil
+}
+
+// Watch reloads the pack whenever the file at path changes. A file that
+// fails to load is logged and the previous pack stays active.
+func (s *selectorSet) Watch(path string) {
+	for range time.Tick(selectorReloadInterval) {
+		info, err := os.Stat(path)
+		if err != nil {
+			slog.Warn("Could not check selector pack", "path", path, "error", err)
+			continue
+		}
+		s.mu.RLock()
+		changed := !info.ModTime().Equal(s.modTime)
+		s.mu.RUnlock()
+		if !changed {
+			continue
+		}
+		if err := s.Load(path); err != nil {
+			slog.Error("Could not reload selector pack, keeping the previous one", "path", path, "error", err)
+			// Do not retry until the file changes again
+			s.mu.Lock()
+			s.modTime = info.ModTime()
+			s.mu.Unlock()
+			continue
+		}
+		slog.Info("Reloaded selector pack", "path", path, "version", s.Version())
+	}
+}
+
+// Version returns the version of the active pack
+func (s *selectorSet) Version() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.pack.Version
+}
+
+// Chain returns the selectors for key in the order they should be tried
+// for a UI in locale: those for the exact locale, then its language, then
+// the default chain
+func (s *selectorSet) Chain(key, locale string) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var chain []string
+	for _, tag := range localeFallbacks(locale) {
+		chain = append(chain, s.pack.Locales[tag][key]...)
+	}
+	return append(chain, s.pack.Selectors[key]...)
+}
+
+// SupportsLocale reports whether the pack has selectors for locale's
+// language, or the language is English, which the default chains cover
+func (s *selectorSet) SupportsLocale(locale string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, tag := range localeFallbacks(locale) {
+		if tag == "en" || s.pack.Locales[tag] != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Locales lists the languages the pack has selectors for, for error messages
+func (s *selectorSet) Locales() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	locales := []string{"en"}
+	for locale := range s.pack.Locales {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Hit records that selector was the first in key's chain to match
+func (s *selectorSet) Hit(key, selector string) {
+	s.mu.Lock()
+	s.hits[[2]string{key, selector}]++
+	s.mu.Unlock()
+	selectorHits.WithLabelValues(key, selector).Inc()
+}
+
+// Status returns the active pack with hit counts
+func (s *selectorSet) Status() SelectorPackStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	status := SelectorPackStatus{
+		Version:  s.pack.Version,
+		Source:   s.source,
+		LoadedAt: s.loadedAt,
+	}
+	status.Selectors = s.chainHits(s.pack.Selectors)
+	if len(s.pack.Locales) > 0 {
+		status.Locales = make(map[string]map[string][]SelectorHits, len(s.pack.Locales))
+		for locale, chains := range s.pack.Locales {
+			status.Locales[locale] = s.chainHits(chains)
+		}
+	}
+	return status
+}
+
+// chainHits pairs every selector in chains with its hit count
+func (s *selectorSet) chainHits(chains map[string][]string) map[string][]SelectorHits {
+	result := make(map[string][]SelectorHits, len(chains))
+	for key, chain := range chains {
+		hits := make([]SelectorHits, len(chain))
+		for i, selector := range chain {
+			hits[i] = SelectorHits{Selector: selector, Hits: s.hits[[2]string{key, selector}]}
+		}
+		result[key] = hits
+	}
+	return result
+}
+
+// handleSelectors reports the active selector pack and which selectors match
+func handleSelectors(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, uiSelectors.Status())
+}
+
+// findSelector returns the first selector in key's chain for locale with a
+// visible match
+func findSelector(page playwright.Page, locale, key string) (string, bool) {
+	selector, ok := firstVisible(page, uiSelectors.Chain(key, locale)...)
+	if ok {
+		uiSelectors.Hit(key, selector)
+	}
+	return selector, ok
+}
+
+// clickSelector clicks the first visible selector in key's chain for locale
+func clickSelector(logger *slog.Logger, page playwright.Page, locale, key, name string) bool {
+	for _, selector := range uiSelectors.Chain(key, locale) {
+		if handleButton(logger, page, selector, name) {
+			uiSelectors.Hit(key, selector)
+			return true
+		}
+	}
+	return false
+}
+
+// fillSelector substitutes {email} and {name} in a selector template with
+// quote-escaped values. It reports false if the template needs a value
+// that is empty, as an empty name would match every element.
+func fillSelector(template, email, name string) (string, bool) {
+	for placeholder, value := range map[string]string{"{email}": email, "{name}": name} {
+		if !strings.Contains(template, placeholder) {
+			continue
+		}
+		if value == "" {
+			return "", false
+		}
+		template = strings.ReplaceAll(template, placeholder, escapeSelectorValue(value))
+	}
+	return template, true
+}
+
+// escapeSelectorValue escapes backslashes and quotes so a value can sit
+// inside a quoted CSS attribute or Playwright text selector
+func escapeSelectorValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `'`, `\'`).Replace(value)
+}
+
+// localePattern matches a language tag such as de, de-AT or zh-Hant-TW
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// normalizeLocale puts a language tag in the conventional case, de-AT
+func normalizeLocale(locale string) string {
+	parts := strings.Split(locale, "-")
+	parts[0] = strings.ToLower(parts[0])
+	for i := 1; i < len(parts); i++ {
+		switch len(parts[i]) {
+		case 2:
+			parts[i] = strings.ToUpper(parts[i])
+		case 4:
+			parts[i] = strings.ToUpper(parts[i][:1]) + strings.ToLower(parts[i][1:])
+		default:
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return strings.Join(parts, "-")
+}
+
+// localeFallbacks returns the pack keys to try for locale, most specific
+// first: "de-at" and then "de" for de-AT
+func localeFallbacks(locale string) []string {
+	var tags []string
+	for tag := strings.ToLower(locale); tag != ""; {
+		tags = append(tags, tag)
+		i := strings.LastIndex(tag, "-")
+		if i < 0 {
+			break
+		}
+		tag = tag[:i]
+	}
+	return tags
+}
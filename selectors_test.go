@@ -0,0 +1,111 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// loadPack loads a custom selector pack into a fresh set
+func loadPack(t *testing.T, pack string) (*selectorSet, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "selectors.json")
+	if err := os.WriteFile(path, []byte(pack), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	set := newSelectorSet()
+	return set, set.Load(path)
+}
+
+func TestSelectorPackMergesWithBuiltin(t *testing.T) {
+	set, err := loadPack(t, `{
+		"version": "custom-1",
+		"selectors": {"meet.join_now": ["button#join"]},
+		"locales": {
+			"de": {"meet.join_now": ["button:has-text('Beitreten')"]},
+			"de-at": {"meet.join_now": ["button:has-text('Mitmachen')"]},
+			"fr": {"meet.ask_to_join": ["button:has-text('Demander à participer')"]}
+		}
+	}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	builtin := newSelectorSet()
+
+	tests := []struct {
+		name, key, locale string
+		want              []string
+	}{
+		{"default chain overridden", "meet.join_now", "en-US", []string{"button#join"}},
+		{"default chain kept", "meet.got_it", "en-US", builtin.Chain("meet.got_it", "en-US")},
+		{"region, language then default", "meet.join_now", "de-AT",
+			[]string{"button:has-text('Mitmachen')", "button:has-text('Beitreten')", "button#join"}},
+		{"language then default", "meet.join_now", "de-DE",
+			[]string{"button:has-text('Beitreten')", "button#join"}},
+		{"built-in locale chain kept", "meet.ask_to_join", "de-AT",
+			slices.Concat(builtin.pack.Locales["de"]["meet.ask_to_join"], builtin.pack.Selectors["meet.ask_to_join"])},
+		{"new locale extends the default", "meet.ask_to_join", "fr-FR",
+			slices.Concat([]string{"button:has-text('Demander à participer')"}, builtin.pack.Selectors["meet.ask_to_join"])},
+		{"unknown locale falls back to default", "meet.join_now", "pt-BR", []string{"button#join"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := set.Chain(tt.key, tt.locale); !slices.Equal(got, tt.want) {
+				t.Errorf("Chain(%q, %q) = %q, want %q", tt.key, tt.locale, got, tt.want)
+			}
+		})
+	}
+
+	if set.Version() != "custom-1" {
+		t.Errorf("Version = %q, want custom-1", set.Version())
+	}
+	for locale, want := range map[string]bool{"de-AT": true, "fr-CA": true, "en-GB": true, "pt-BR": false} {
+		if got := set.SupportsLocale(locale); got != want {
+			t.Errorf("SupportsLocale(%q) = %v, want %v", locale, got, want)
+		}
+	}
+}
+
+func TestSelectorPackRejectsBadPacks(t *testing.T) {
+	tests := []struct {
+		name, pack, wantErr string
+	}{
+		{"unknown key", `{"version": "x", "selectors": {"meet.join_later": ["button"]}}`, "unknown selector key"},
+		{"unknown key in a locale", `{"version": "x", "selectors": {}, "locales": {"de": {"meet.typo": ["button"]}}}`, "unknown selector key"},
+		{"empty chain", `{"version": "x", "selectors": {"meet.join_now": []}}`, "has no selectors"},
+		{"blank selector", `{"version": "x", "selectors": {"meet.join_now": [" "]}}`, "empty selector"},
+		{"upper-case locale", `{"version": "x", "selectors": {}, "locales": {"de-AT": {"meet.join_now": ["button"]}}}`, "lower-case language tag"},
+		{"missing version", `{"selectors": {}}`, "version is required"},
+		{"unknown field", `{"version": "x", "selectors": {}, "extra": true}`, "unknown field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := loadPack(t, tt.pack)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load = %v, want an error containing %q", err, tt.wantErr)
+			}
+			if set.Version() != newSelectorSet().Version() {
+				t.Errorf("a rejected pack replaced the built-in one")
+			}
+		})
+	}
+}
+
+func TestLocaleFallbacks(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   []string
+	}{
+		{"de-AT", []string{"de-at", "de"}},
+		{"zh-Hant-TW", []string{"zh-hant-tw", "zh-hant", "zh"}},
+		{"es", []string{"es"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := localeFallbacks(tt.locale); !slices.Equal(got, tt.want) {
+			t.Errorf("localeFallbacks(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
@@ -1,40 +1,535 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
-	"log"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	// queueRetryAfter is the back-off suggested to clients when the queue is full
+	queueRetryAfter = 60 * time.Second
+	// sseKeepAlive is how often an idle event stream gets a comment line
+	sseKeepAlive = 15 * time.Second
 )
 
 type MeetingRequest struct {
-	MeetingURL string `json:"meeting_url"`
-	BotName  string `json:"bot_name"`
-	GuestEmail      string `json:"email"`
-	GuestName	   string `json:"name"`
+	MeetingURL  string `json:"meeting_url"`
+	BotName     string `json:"bot_name"`
+	GuestEmail  string `json:"email"`
+	GuestName   string `json:"name"`
+	CallbackURL string `json:"callback_url,omitempty"`
+	// Passcode is typed into the join form of platforms that ask for one
+	// when the meeting URL does not carry it
+	Passcode string `json:"passcode,omitempty"`
+	// Locale sets the browser language, and with it the meeting UI language
+	// and the selectors used to drive it
+	Locale string `json:"locale,omitempty"`
+
+	// StartAt schedules the bot for a later meeting; JoinEarly moves the
+	// launch that much earlier so the bot is in the lobby on time
+	StartAt   *time.Time `json:"start_at,omitempty"`
+	JoinEarly Duration   `json:"join_early,omitempty"`
+}
+
+// RescheduleRequest moves a scheduled join to a new time
+type RescheduleRequest struct {
+	StartAt   *time.Time `json:"start_at"`
+	JoinEarly *Duration  `json:"join_early,omitempty"`
+}
+
+// StartMeetingResponse is returned when a meeting bot job is accepted
+type StartMeetingResponse struct {
+	JobID         string     `json:"job_id"`
+	Status        JobStatus  `json:"status"`
+	StatusURL     string     `json:"status_url"`
+	QueuePosition int        `json:"queue_position"`
+	LaunchAt      *time.Time `json:"launch_at,omitempty"`
+	// Attached is set when the request joined a bot already in the meeting
+	Attached bool `json:"attached,omitempty"`
+	// TargetIgnored is set when the request attached to a bot waiting for a
+	// different person, so its email and name have no effect
+	TargetIgnored bool `json:"target_ignored,omitempty"`
+}
+
+// MeetingStatusResponse is the job state plus its place in the wait queue
+type MeetingStatusResponse struct {
+	JobInfo
+	QueuePosition int `json:"queue_position"`
 }
 
+var (
+	events    = NewEventBus()
+	jobs      = NewJobStore(events)
+	scheduler *Scheduler
+)
+
 func main() {
-	http.HandleFunc("/start-meeting", handleStartMeeting)
-	log.Println("API server running on :8080")
-	log.Fatal(http.ListenAndServe(":8080", nil))
+	if len(os.Args) > 1 && os.Args[1] == "keygen" {
+		if err := runKeygen(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	maxConcurrent := flag.Int("max-concurrent", 5, "maximum number of meeting bots running at once")
+	maxQueued := flag.Int("max-queue", 50, "maximum number of jobs waiting for a free bot slot")
+	maxProcessing := flag.Int("max-processing", 2, "maximum number of uploads and reprocessing runs transcribed or summarized at once")
+	dataDir := flag.String("data-dir", "data", "directory for the persistent job store")
+	shutdownMode := flag.String("shutdown-mode", "drain", `on SIGTERM, "drain" lets bots finish their meetings, "leave" makes them leave`)
+	drainTimeout := flag.Duration("drain-timeout", 30*time.Minute, "how long to wait for bots to finish in drain mode before making them leave")
+	keysPath := flag.String("keys-file", "apikeys.json", `API key config, create keys with "meeting-bot keygen"`)
+	requireAuth := flag.Bool("auth", true, "require an API key on every endpoint")
+	uploadMB := flag.Int64("max-upload-mb", maxUploadSize>>20, "largest recording accepted by POST /recordings, in MB")
+	grpcAddr := flag.String("grpc-addr", ":9090", `address of the gRPC API, "" to disable it`)
+	flag.StringVar(&publicURL, "public-url", "", "external base URL of this API, used for artifact links in webhooks")
+	jitsiHostList := flag.String("jitsi-hosts", strings.Join(jitsiHosts, ","), "comma separated hosts that serve Jitsi Meet, e.g. a self-hosted meet.example.com")
+	selectorsPath := flag.String("selectors-file", "", "JSON selector pack overriding the built-in selectors, reloaded when the file changes")
+	logFormat := flag.String("log-format", "text", `log output format, "text" or "json"`)
+	logLevel := flag.String("log-level", "info", "lowest level logged to stderr: debug, info, warn or error")
+	flag.Parse()
+
+	if err := initLogging(*logFormat, *logLevel); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	if *shutdownMode != "drain" && *shutdownMode != "leave" {
+		fatal("Invalid -shutdown-mode, expected drain or leave", "shutdown_mode", *shutdownMode)
+	}
+	maxUploadSize = *uploadMB << 20
+	setJitsiHosts(*jitsiHostList)
+	if *selectorsPath != "" {
+		if err := uiSelectors.Load(*selectorsPath); err != nil {
+			fatal("Failed to load the selector pack", "error", err)
+		}
+		slog.Info("Loaded selector pack", "path", *selectorsPath, "version", uiSelectors.Version())
+		go uiSelectors.Watch(*selectorsPath)
+	}
+
+	if *requireAuth {
+		keys, err := LoadKeyStore(*keysPath)
+		if err != nil {
+			fatal("Failed to load API keys (use -auth=false to run without authentication)", "error", err)
+		}
+		apiKeys = keys
+	} else {
+		slog.Warn("API key authentication is disabled")
+	}
+
+	db, err := OpenJobDB(filepath.Join(*dataDir, "jobs.db"))
+	if err != nil {
+		fatal("Failed to open the job store", "error", err)
+	}
+	defer db.Close()
+	restored, err := jobs.Restore(db)
+	if err != nil {
+		fatal("Failed to load jobs", "error", err)
+	}
+
+	// Unload sinks left behind by a previous run that did not shut down cleanly
+	cleanupAudioSinks()
+
+	scheduler = NewScheduler(*maxConcurrent, *maxQueued, *maxProcessing, RunMeetingBot)
+	resumeJobs(restored)
+	if webhookSecret == "" {
+		slog.Warn("MEETAI_WEBHOOK_SECRET is not set, completion webhooks will be unsigned")
+	}
+
+	registerRoutes(http.DefaultServeMux)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{Addr: ":8080", Handler: requireAPIKey(http.DefaultServeMux)}
+	go func() {
+		slog.Info("API server running", "addr", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fatal("API server failed", "error", err)
+		}
+	}()
+
+	var grpcSrv *grpc.Server
+	if *grpcAddr != "" {
+		lis, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			fatal("Failed to listen for gRPC", "addr", *grpcAddr, "error", err)
+		}
+		grpcSrv = newGRPCServer()
+		go func() {
+			slog.Info("gRPC server running", "addr", *grpcAddr)
+			if err := grpcSrv.Serve(lis); err != nil {
+				fatal("gRPC server failed", "error", err)
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	// A second signal kills the process straight away
+	stop()
+	shutdown(srv, grpcSrv, *shutdownMode == "leave", *drainTimeout)
 }
 
-func handleStartMeeting(w http.ResponseWriter, r *http.Request) {
+// shutdown stops taking new jobs, lets in-flight bots finish or leave, waits
+// for their recordings to be processed and webhooks to be sent, and unloads
+// every audio sink before the process exits
+func shutdown(srv *http.Server, grpcSrv *grpc.Server, leave bool, drainTimeout time.Duration) {
+	if leave {
+		slog.Info("Shutting down, asking running bots to leave")
+	} else {
+		slog.Info("Shutting down, waiting for running bots to finish", "timeout", drainTimeout)
+	}
 
-	// Add semaphore for concurrency control
-	var sem = make(chan struct{}, 5) // Limit 5 concurrent meetings
+	drainCtx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+	defer cancel()
+	scheduler.Shutdown(drainCtx, leave)
+	waitForWebhooks(webhookFlushTimeout())
 
+	httpCtx, cancelHTTP := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelHTTP()
+	if err := srv.Shutdown(httpCtx); err != nil {
+		srv.Close()
+	}
+	if grpcSrv != nil {
+		stopGRPC(grpcSrv, 10*time.Second)
+	}
+
+	cleanupAudioSinks()
+	slog.Info("Shutdown complete")
+}
+
+func handleStartMeeting(w http.ResponseWriter, r *http.Request) {
 	var req MeetingRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		http.Error(w, "Invalid request", http.StatusBadRequest)
-		return
-	}
-    sem <- struct{}{}
-    go func() {
-        defer func() { <-sem }()
-        if err := RunMeetingBot(req.MeetingURL, req.BotName, req.GuestEmail, req.GuestName); err != nil {
-            log.Printf("Meeting bot error: %v", err)
-        }
-    }()
-	w.Write([]byte("Meeting bot started. You will receive the summary when done."))
+	if err := decodeJSON(r, &req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	idempotencyKey := r.Header.Get("Idempotency-Key")
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		writeValidationError(w, &ValidationError{Fields: []FieldError{{
+			Field: "Idempotency-Key", Message: fmt.Sprintf("must be at most %d characters", maxIdempotencyKeyLength),
+		}}})
+		return
+	}
+
+	resp, replayed, err := startMeeting(apiKeyFrom(r), req, idempotencyKey)
+	switch {
+	case errors.Is(err, ErrIdempotencyMismatch):
+		writeError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	case errors.Is(err, ErrQuotaExceeded):
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	case errors.Is(err, ErrQueueFull):
+		w.Header().Set("Retry-After", strconv.Itoa(int(queueRetryAfter.Seconds())))
+		writeError(w, http.StatusTooManyRequests, "Too many meetings queued, try again later")
+		return
+	case errors.Is(err, ErrShuttingDown):
+		writeError(w, http.StatusServiceUnavailable, "Service is shutting down, try again later")
+		return
+	case err != nil:
+		slog.Error("Error starting meeting", "meeting_url", req.MeetingURL, "error", err)
+		writeError(w, http.StatusInternalServerError, "Could not start the meeting")
+		return
+	}
+	if replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	writeJSON(w, http.StatusAccepted, resp)
+}
+
+// startMeeting checks the caller's quota, creates a job for a validated
+// request and hands it to the scheduler. It is shared by the HTTP and gRPC APIs.
+//
+// A request retried with the same idempotency key gets the original job back
+// with replayed set. A request for a meeting that already has an active bot
+// attaches to that job as a subscriber instead of launching a second bot.
+func startMeeting(key *APIKey, req MeetingRequest, idempotencyKey string) (resp StartMeetingResponse, replayed bool, err error) {
+	var keyID string
+	if key != nil {
+		keyID = key.ID
+	}
+	hash := requestHash(req)
+
+	// Check the caller's quota and create the job atomically
+	quotaMu.Lock()
+	if idempotencyKey != "" {
+		if match, ok := jobs.FindIdempotent(keyID, idempotencyKey); ok {
+			quotaMu.Unlock()
+			if match.hash != hash {
+				return StartMeetingResponse{}, false, ErrIdempotencyMismatch
+			}
+			resp := startResponse(match.job, 0, match.attached)
+			resp.TargetIgnored = match.targetIgnored
+			return resp, true, nil
+		}
+	}
+
+	// Attaching shares a bot that is already counted against its creator, so
+	// it does not use the caller's quota
+	if !req.LaunchAt().After(time.Now()) {
+		if job, ok := jobs.FindActive(req.MeetingURL); ok {
+			targetIgnored := !sameTarget(job.Request(), req)
+			job.Attach(Subscriber{
+				APIKeyID:       keyID,
+				CallbackURL:    req.CallbackURL,
+				IdempotencyKey: idempotencyKey,
+				RequestHash:    hash,
+				AttachedAt:     time.Now(),
+				TargetIgnored:  targetIgnored,
+			})
+			quotaMu.Unlock()
+			job.Logger().Info("Request attached to the bot already in the meeting", "target_ignored", targetIgnored)
+			resp := startResponse(job, scheduler.Position(job.ID()), true)
+			resp.TargetIgnored = targetIgnored
+			return resp, false, nil
+		}
+	}
+
+	if err := checkQuota(key, req.LaunchAt().After(time.Now())); err != nil {
+		quotaMu.Unlock()
+		return StartMeetingResponse{}, false, err
+	}
+	job := jobs.Create(req, keyID)
+	if idempotencyKey != "" {
+		job.SetIdempotencyKey(idempotencyKey, hash)
+	}
+	quotaMu.Unlock()
+
+	var position int
+	if job.Status() == StatusScheduled {
+		err = scheduler.Schedule(job)
+	} else {
+		position, err = scheduler.Submit(job)
+	}
+	if err != nil {
+		jobs.Delete(job.ID())
+		return StartMeetingResponse{}, false, err
+	}
+	return startResponse(job, position, false), false, nil
+}
+
+// startResponse describes a job accepted by startMeeting
+func startResponse(job *Job, position int, attached bool) StartMeetingResponse {
+	if position == 0 {
+		position = scheduler.Position(job.ID())
+	}
+	resp := StartMeetingResponse{
+		JobID:         job.ID(),
+		Status:        job.Status(),
+		StatusURL:     "/meetings/" + job.ID(),
+		QueuePosition: position,
+		Attached:      attached,
+	}
+	if resp.Status == StatusScheduled {
+		launchAt := job.Request().LaunchAt()
+		resp.LaunchAt = &launchAt
+	}
+	return resp
+}
+
+// handleGetMeeting reports the lifecycle state of a single job
+func handleGetMeeting(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobForRequest(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, MeetingStatusResponse{
+		JobInfo:       job.InfoFor(apiKeyFrom(r)),
+		QueuePosition: scheduler.Position(job.ID()),
+	})
+}
+
+// handleRescheduleMeeting moves a pending scheduled join to a new start time
+func handleRescheduleMeeting(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var req RescheduleRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+	joinEarly := job.Request().JoinEarly
+	if req.JoinEarly != nil {
+		joinEarly = *req.JoinEarly
+	}
+
+	if !scheduler.Reschedule(job, *req.StartAt, joinEarly) {
+		writeError(w, http.StatusConflict, "Meeting job is not waiting for a scheduled start")
+		return
+	}
+	writeJSON(w, http.StatusOK, job.InfoFor(apiKeyFrom(r)))
+}
+
+// handleListScheduled lists the joins waiting for their start time
+func handleListScheduled(w http.ResponseWriter, r *http.Request) {
+	pending := scheduler.Scheduled()
+	infos := make([]JobInfo, 0, len(pending))
+	for _, job := range pending {
+		if canAccess(r, job) {
+			infos = append(infos, job.InfoFor(apiKeyFrom(r)))
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"meetings": infos})
+}
+
+// handleCancelMeeting pulls a bot out of its meeting. The "recording" query
+// parameter chooses whether the partial recording is processed (the default)
+// or discarded. A caller that only attached to the job is detached instead,
+// leaving the bot running for everyone else.
+func handleCancelMeeting(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobForRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var discard bool
+	switch r.URL.Query().Get("recording") {
+	case "", "process":
+	case "discard":
+		discard = true
+	default:
+		writeError(w, http.StatusBadRequest, "recording must be \"process\" or \"discard\"")
+		return
+	}
+
+	if detachSubscriber(apiKeyFrom(r), job) {
+		writeJSON(w, http.StatusAccepted, MeetingStatusResponse{
+			JobInfo:       job.InfoFor(apiKeyFrom(r)),
+			QueuePosition: scheduler.Position(job.ID()),
+		})
+		return
+	}
+	switch err := scheduler.Cancel(job, discard); {
+	case errors.Is(err, ErrJobProcessing):
+		writeError(w, http.StatusConflict, "Meeting has already been recorded and is being processed")
+		return
+	case err != nil:
+		writeError(w, http.StatusConflict, "Meeting job has already finished")
+		return
+	}
+
+	writeJSON(w, http.StatusAccepted, MeetingStatusResponse{
+		JobInfo:       job.InfoFor(apiKeyFrom(r)),
+		QueuePosition: scheduler.Position(job.ID()),
+	})
+}
+
+// handleMeetingEvents streams a job's events as Server-Sent Events. Events
+// already published are replayed first, skipping any up to Last-Event-ID.
+func handleMeetingEvents(w http.ResponseWriter, r *http.Request) {
+	job, ok := jobForRequest(w, r)
+	if !ok {
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "Streaming not supported")
+		return
+	}
+
+	var lastID int64
+	if header := r.Header.Get("Last-Event-ID"); header != "" {
+		lastID, _ = strconv.ParseInt(header, 10, 64)
+	}
+
+	history, stream, unsubscribe := events.Subscribe(job.ID())
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, event := range history {
+		if event.ID > lastID {
+			writeSSE(w, event)
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				return
+			}
+			writeSSE(w, event)
+			flusher.Flush()
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+// writeSSE writes one event in text/event-stream framing
+func writeSSE(w http.ResponseWriter, event Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("Error encoding event", "error", err)
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+}
+
+// jobForRequest looks up the job named in the URL path, answering 404 if it
+// does not exist or belongs to another API key
+func jobForRequest(w http.ResponseWriter, r *http.Request) (*Job, bool) {
+	job, ok := jobs.Get(r.PathValue("id"))
+	if !ok || !canAccess(r, job) {
+		writeError(w, http.StatusNotFound, "Meeting job not found")
+		return nil, false
+	}
+	return job, true
+}
+
+// writeJSON encodes v as the JSON response body
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("Error writing response", "error", err)
+	}
+}
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError sends a JSON error body with the given status code
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, ErrorResponse{Error: message})
 }
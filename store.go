@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var jobsBucket = []byte("jobs")
+
+// JobDB persists job state in an embedded bbolt database so that jobs
+// survive restarts
+type JobDB struct {
+	db *bolt.DB
+}
+
+// OpenJobDB opens (or creates) the job database at path
+func OpenJobDB(path string) (*JobDB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, fmt.Errorf("failed to create data directory: %v", err)
+	}
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open job database: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(jobsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize job database: %v", err)
+	}
+	return &JobDB{db: db}, nil
+}
+
+// storedJob is the database record of a job. It adds the fields JobInfo
+// keeps out of API responses.
+type storedJob struct {
+	JobInfo
+	IdempotencyKey string       `json:"idempotency_key,omitempty"`
+	RequestHash    string       `json:"request_hash,omitempty"`
+	Subscribers    []Subscriber `json:"subscribers,omitempty"`
+}
+
+// Save writes the current state of a job
+func (d *JobDB) Save(info JobInfo) error {
+	data, err := json.Marshal(storedJob{
+		JobInfo:        info,
+		IdempotencyKey: info.IdempotencyKey,
+		RequestHash:    info.RequestHash,
+		Subscribers:    info.Subscribers,
+	})
+	if err != nil {
+		return err
+	}
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Put([]byte(info.ID), data)
+	})
+}
+
+// Delete removes a job
+func (d *JobDB) Delete(id string) error {
+	return d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).Delete([]byte(id))
+	})
+}
+
+// LoadAll reads every stored job
+func (d *JobDB) LoadAll() ([]JobInfo, error) {
+	var infos []JobInfo
+	err := d.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(jobsBucket).ForEach(func(k, v []byte) error {
+			var stored storedJob
+			if err := json.Unmarshal(v, &stored); err != nil {
+				return fmt.Errorf("corrupt job %s: %v", k, err)
+			}
+			info := stored.JobInfo
+			info.IdempotencyKey = stored.IdempotencyKey
+			info.RequestHash = stored.RequestHash
+			info.Subscribers = stored.Subscribers
+			infos = append(infos, info)
+			return nil
+		})
+	})
+	return infos, err
+}
+
+// Close closes the database
+func (d *JobDB) Close() error {
+	return d.db.Close()
+}
+
+// resumeJobs picks up the jobs that were in flight when the service stopped.
+// Scheduled and queued jobs go back to the scheduler, recordings that never
+// finished processing resume from their last completed stage, and bots that
+// had not reached the meeting yet are failed since their session is gone.
+func resumeJobs(restored []*Job) {
+	sort.Slice(restored, func(i, j int) bool {
+		return restored[i].Info().CreatedAt.Before(restored[j].Info().CreatedAt)
+	})
+
+	for _, job := range restored {
+		switch job.Status() {
+		case StatusScheduled:
+			job.Logger().Info("Restoring scheduled job")
+			scheduler.Schedule(job)
+		case StatusQueued:
+			job.Logger().Info("Requeueing job")
+			scheduler.Enqueue(job)
+		case StatusLaunching, StatusJoining, StatusInLobby:
+			job.Logger().Warn("Job was interrupted before joining the meeting")
+			job.Fail(errors.New("interrupted by a service restart before joining the meeting"))
+			notifyCompletion(job)
+		case StatusRecording, StatusTranscribing, StatusSummarizing:
+			job.Logger().Info("Resuming processing")
+			scheduler.Go(func() { resumeProcessing(job) })
+		}
+	}
+}
+
+// resumeProcessing finishes transcription and summarization for a job whose
+// bot is gone but whose recording is still on disk
+func resumeProcessing(job *Job) {
+	if job.PendingReprocess() != nil {
+		runReprocess(job)
+		return
+	}
+
+	audioFilePath, ok := job.Artifact(ArtifactRecording)
+	if !ok {
+		job.Fail(errors.New("interrupted by a service restart and no recording was found"))
+	} else if err := processRecording(job, audioFilePath); err != nil {
+		job.Fail(err)
+	}
+	notifyCompletion(job)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	// teamsLoadTimeout is how long the Teams web client may take to show
+	// the pre-join screen; it is slow, especially on a cold start
+	teamsLoadTimeout = time.Minute
+	// teamsJoinTimeout is how long Join waits for the meeting or lobby
+	// after clicking "Join now"
+	teamsJoinTimeout = time.Minute
+)
+
+var (
+	// teamsMeetupPattern matches classic join links,
+	// /l/meetup-join/19:meeting_...@thread.v2/0
+	teamsMeetupPattern = regexp.MustCompile(`^/l/meetup-join/[^/]+/[^/]+$`)
+	// teamsMeetPattern matches short join links with a numeric meeting ID, /meet/1234567890
+	teamsMeetPattern = regexp.MustCompile(`^/meet/[0-9]{6,20}$`)
+)
+
+// teamsQueryParams are the query parameters a join link needs: the tenant
+// and organizer context of classic links and the passcode of short links
+var teamsQueryParams = []string{"context", "p"}
+
+// microsoftTeams drives the anonymous join flow of the Microsoft Teams web client
+type microsoftTeams struct{}
+
+func (microsoftTeams) Name() string {
+	return "Microsoft Teams"
+}
+
+func (microsoftTeams) Matches(u *url.URL) bool {
+	switch strings.ToLower(u.Hostname()) {
+	case "teams.microsoft.com", "teams.live.com":
+		return true
+	}
+	return false
+}
+
+// NormalizeURL returns the join link over https with only the query
+// parameters Teams needs to find the meeting
+func (microsoftTeams) NormalizeURL(u *url.URL) (string, error) {
+	path := strings.TrimRight(u.EscapedPath(), "/")
+	if !teamsMeetupPattern.MatchString(path) && !teamsMeetPattern.MatchString(path) {
+		return "", errors.New("must be a Teams join link like https://teams.microsoft.com/l/meetup-join/... or https://teams.microsoft.com/meet/...")
+	}
+
+	query := u.Query()
+	kept := url.Values{}
+	for _, name := range teamsQueryParams {
+		if value := query.Get(name); value != "" {
+			kept.Set(name, value)
+		}
+	}
+	normalized := "https://" + strings.ToLower(u.Hostname()) + path
+	if len(kept) > 0 {
+		normalized += "?" + kept.Encode()
+	}
+	return normalized, nil
+}
+
+// ShowsEmails is false because anonymous guests only see display names
+func (microsoftTeams) ShowsEmails() bool {
+	return false
+}
+
+var (
+	teamsInMeeting = []string{
+		"button[data-tid='hangup-main-btn']",
+		"button#hangup-button",
+		"button[aria-label='Leave']",
+		"button[aria-label*='Hang up']",
+	}
+	teamsInLobby = []string{
+		"text=/should let you in soon/i",
+		"text=/let people know you're waiting/i",
+		"[data-tid='lobby-screen']",
+	}
+	teamsNameInput = []string{
+		"input[data-tid='prejoin-display-name-input']",
+		"input[placeholder='Type your name']",
+	}
+	teamsLauncher = []string{
+		"button[data-tid='joinOnWeb']",
+		"button:has-text('Continue on this browser')",
+		"button:has-text('Join on the web instead')",
+		"button:has-text('Use the web app instead')",
+	}
+)
+
+// Join picks the browser on the launcher page, enters the bot's name on
+// the pre-join screen with camera and microphone off and joins. Meetings
+// with a lobby leave the bot waiting to be admitted.
+func (microsoftTeams) Join(logger *slog.Logger, page playwright.Page, botName, _ string) (bool, error) {
+	// The launcher offers the desktop app first. It can render well after
+	// navigation, so wait for either it or the pre-join screen.
+	deadline := time.Now().Add(teamsLoadTimeout)
+	selector, ok := waitForVisible(page, teamsLoadTimeout, slices.Concat(teamsNameInput, teamsLauncher)...)
+	if ok && !slices.Contains(teamsNameInput, selector) {
+		clickFirstVisible(logger, page, "Continue on this browser", teamsLauncher...)
+		selector, ok = waitForVisible(page, time.Until(deadline), teamsNameInput...)
+	}
+	if !ok {
+		return false, errors.New("the Teams pre-join screen did not load")
+	}
+	if err := page.Locator(selector).First().Fill(botName); err != nil {
+		return false, errors.New("could not enter the bot name: " + err.Error())
+	}
+	logger.Info("Entered guest name")
+
+	// Switches read aria-checked="true" while the device is on
+	clickFirstVisible(logger, page, "Turn camera off",
+		"[data-tid='toggle-video'][aria-checked='true']",
+		"input[title*='Turn camera off' i]",
+	)
+	clickFirstVisible(logger, page, "Mute microphone",
+		"[data-tid='toggle-mute'][aria-checked='true']",
+		"input[title*='Mute mic' i]",
+	)
+
+	if !clickFirstVisible(logger, page, "Join now",
+		"button[data-tid='prejoin-join-button']",
+		"button:has-text('Join now')",
+	) {
+		return false, errors.New("could not find the Join now button")
+	}
+	// Shown when the browser reports no usable devices
+	clickFirstVisible(logger, page, "Continue without audio or video",
+		"button:has-text('Continue without audio or video')",
+	)
+
+	// The lobby shows the hang up button too, so it is checked first
+	deadline = time.Now().Add(teamsJoinTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := firstVisible(page, teamsInLobby...); ok {
+			logger.Info("Requested to join the meeting")
+			return true, nil
+		}
+		if _, ok := firstVisible(page, teamsInMeeting...); ok {
+			return false, nil
+		}
+		time.Sleep(time.Second)
+	}
+	return false, errors.New("the meeting did not load after joining")
+}
+
+// Admission reports whether the bot has been let in or turned away. The
+// bot counts as admitted once the lobby text is gone.
+func (microsoftTeams) Admission(page playwright.Page) (admitted, denied bool) {
+	_, waiting := firstVisible(page, teamsInLobby...)
+	_, inMeeting := firstVisible(page, teamsInMeeting...)
+	admitted = inMeeting && !waiting
+	_, denied = firstVisible(page,
+		"text=/denied access to the meeting/i",
+		"text=/No one responded to your request/i",
+	)
+	return admitted, denied
+}
+
+// MeetingEnded reports whether the bot was removed, the meeting was ended
+// or the page moved to the post-call screen
+func (microsoftTeams) MeetingEnded(page playwright.Page) (string, bool) {
+	return firstVisible(page,
+		"text=/You('ve| have) been removed from (this|the) meeting/i",
+		"text=/meeting has ended/i",
+		"text=/You (have )?left the meeting/i",
+		"button[data-tid='calling-retry-rejoinbutton']",
+		"button:has-text('Rejoin')",
+	)
+}
+
+// IsPersonInMeeting looks for the person's display name in the roster.
+// Guests do not see emails, so the email is only matched if the person
+// used it as their name.
+func (t microsoftTeams) IsPersonInMeeting(logger *slog.Logger, page playwright.Page, email, name string) bool {
+	t.openRoster(logger, page)
+
+	roster := []string{
+		"[data-tid^='participantsInCall-']",
+		"[data-cid='roster-participant']",
+		"[role='tree'] [role='treeitem']",
+	}
+	return containsText(page, name, roster...) || containsText(page, email, roster...)
+}
+
+// openRoster opens the People panel unless it is already open
+func (microsoftTeams) openRoster(logger *slog.Logger, page playwright.Page) {
+	if _, open := firstVisible(page, "[data-tid='roster-list']", "[aria-label='Participants'][role='tree']"); open {
+		return
+	}
+	if clickFirstVisible(logger, page, "People", "button[data-tid='roster-button']", "button#roster-button", "button[aria-label^='People']") {
+		time.Sleep(time.Second)
+	}
+}
+
+// Leave clicks the hang up button
+func (microsoftTeams) Leave(logger *slog.Logger, page playwright.Page) {
+	leaveByButton(logger, page, teamsInMeeting...)
+}
@@ -1,3 +1,4 @@
+import argparse
 import sys
 import whisper
 import warnings
@@ -5,18 +6,20 @@ import warnings
 warnings.filterwarnings("ignore", message=".*FP16 is not supported on CPU.*")
 warnings.filterwarnings("ignore", category=UserWarning)
 
-def transcribe_audio(file_path):
+def transcribe_audio(file_path, model_name="base", language=None):
     try:
-        model = whisper.load_model("base")
-        result = model.transcribe(file_path, fp16=False)  # Explicitly disable FP16
+        model = whisper.load_model(model_name)
+        result = model.transcribe(file_path, fp16=False, language=language)  # Explicitly disable FP16
         return result["text"]
     except Exception as e:
         print(f"CRITICAL_ERROR: {str(e)}", file=sys.stderr)
         sys.exit(1)
 
 if __name__ == "__main__":
-    if len(sys.argv) < 2:
-        print("Usage: python transcribe.py <audio_file>")
-        sys.exit(1)
-    
-    print(transcribe_audio(sys.argv[1]))
\ No newline at end of file
+    parser = argparse.ArgumentParser(usage="python transcribe.py <audio_file> [--model NAME] [--language CODE]")
+    parser.add_argument("audio_file")
+    parser.add_argument("--model", default="base", help="Whisper model, e.g. base, small, medium")
+    parser.add_argument("--language", default=None, help="spoken language, detected when omitted")
+    args = parser.parse_args()
+
+    print(transcribe_audio(args.audio_file, args.model, args.language))
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// maxUploadSize bounds the size of an uploaded recording in bytes
+var maxUploadSize int64 = 500 << 20
+
+// uploadExtensions are the audio formats accepted by POST /recordings
+var uploadExtensions = map[string]bool{
+	".mp3":  true,
+	".wav":  true,
+	".m4a":  true,
+	".webm": true,
+}
+
+// UploadInfo describes a recording that was uploaded instead of recorded by a bot
+type UploadInfo struct {
+	Filename string `json:"filename"`
+	Size     int64  `json:"size"`
+}
+
+// reserveProcessing takes a post-processing slot for the request, or
+// writes the error and returns false when none is free
+func reserveProcessing(w http.ResponseWriter) bool {
+	err := scheduler.Reserve()
+	switch {
+	case errors.Is(err, ErrProcessingBusy):
+		w.Header().Set("Retry-After", strconv.Itoa(int(queueRetryAfter.Seconds())))
+		writeError(w, http.StatusTooManyRequests, "Too many recordings being processed, try again later")
+		return false
+	case err != nil:
+		writeError(w, http.StatusServiceUnavailable, "Service is shutting down, try again later")
+		return false
+	}
+	return true
+}
+
+// handleUploadRecording accepts a multipart audio upload in the "file" field
+// and runs it through the transcribe, summarize and deliver pipeline. An
+// optional "callback_url" field receives the completion webhook.
+func handleUploadRecording(w http.ResponseWriter, r *http.Request) {
+	// Take a processing slot before reading what may be a large body
+	if !reserveProcessing(w) {
+		return
+	}
+	reserved := true
+	defer func() {
+		if reserved {
+			scheduler.Release()
+		}
+	}()
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxUploadSize)
+	reader, err := r.MultipartReader()
+	if err != nil {
+		writeValidationError(w, &ValidationError{Fields: []FieldError{{Field: "body", Message: "must be multipart/form-data"}}})
+		return
+	}
+
+	var req MeetingRequest
+	var upload UploadInfo
+	var audioFilePath string
+	// Remove the saved file unless a job takes ownership of it
+	defer func() {
+		if audioFilePath != "" {
+			os.Remove(audioFilePath)
+		}
+	}()
+
+	verr := &ValidationError{}
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			writeUploadError(w, err)
+			return
+		}
+
+		switch part.FormName() {
+		case "file":
+			if audioFilePath != "" {
+				verr.add("file", "only one file can be uploaded")
+				continue
+			}
+			upload.Filename = filepath.Base(part.FileName())
+			ext := strings.ToLower(filepath.Ext(upload.Filename))
+			if !uploadExtensions[ext] {
+				verr.add("file", "must be an mp3, wav, m4a or webm file")
+				continue
+			}
+			audioFilePath, upload.Size, err = saveUpload(part, ext)
+			if err != nil {
+				writeUploadError(w, err)
+				return
+			}
+		case "callback_url":
+			value, err := io.ReadAll(io.LimitReader(part, 2048))
+			if err != nil {
+				writeUploadError(w, err)
+				return
+			}
+			req.CallbackURL = strings.TrimSpace(string(value))
+		default:
+			verr.add(part.FormName(), "is not a known field")
+		}
+	}
+
+	switch {
+	case audioFilePath == "" && len(verr.Fields) == 0:
+		verr.add("file", "is required")
+	case audioFilePath != "" && upload.Size == 0:
+		verr.add("file", "is empty")
+	}
+	if req.CallbackURL != "" && !validCallbackURL(req.CallbackURL) {
+		verr.add("callback_url", "must be an absolute http or https URL")
+	}
+	if err := verr.errOrNil(); err != nil {
+		writeValidationError(w, err)
+		return
+	}
+
+	// Uploads do not use a bot but still count towards the daily limit
+	quotaMu.Lock()
+	if err := checkQuota(apiKeyFrom(r), true); err != nil {
+		quotaMu.Unlock()
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	job := jobs.CreateUpload(req, apiKeyID(r), upload)
+	quotaMu.Unlock()
+	path := audioFilePath
+	job.SetArtifact(ArtifactRecording, path)
+	recordingBytes.WithLabelValues(sourceUpload).Observe(float64(upload.Size))
+
+	scheduler.RunReserved(func() { processUpload(job, path) })
+	reserved = false
+	job.Logger().Info("Processing uploaded recording", "filename", upload.Filename, "size", upload.Size)
+	audioFilePath = ""
+
+	writeJSON(w, http.StatusAccepted, StartMeetingResponse{
+		JobID:     job.ID(),
+		Status:    job.Status(),
+		StatusURL: "/meetings/" + job.ID(),
+	})
+}
+
+// saveUpload streams an uploaded file into the recordings folder
+func saveUpload(src io.Reader, ext string) (string, int64, error) {
+	if err := os.MkdirAll(recordingFolder, os.ModePerm); err != nil {
+		return "", 0, err
+	}
+	file, err := os.CreateTemp(recordingFolder, "upload_*"+ext)
+	if err != nil {
+		return "", 0, err
+	}
+	defer file.Close()
+
+	size, err := io.Copy(file, src)
+	if err != nil {
+		os.Remove(file.Name())
+		return "", 0, err
+	}
+	return file.Name(), size, nil
+}
+
+// writeUploadError reports a failure while reading the upload
+func writeUploadError(w http.ResponseWriter, err error) {
+	var tooLarge *http.MaxBytesError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("Uploads are limited to %d MB", maxUploadSize>>20))
+		return
+	case errors.As(err, &pathErr):
+		slog.Error("Error storing upload", "error", err)
+		writeError(w, http.StatusInternalServerError, "Could not store the upload")
+		return
+	}
+	writeValidationError(w, &ValidationError{Fields: []FieldError{{Field: "body", Message: err.Error()}}})
+}
+
+// processUpload runs an uploaded recording through the pipeline and sends
+// the completion webhook
+func processUpload(job *Job, audioFilePath string) {
+	if err := processRecording(job, audioFilePath); err != nil {
+		job.Logger().Error("Processing error", "error", err)
+		job.Fail(err)
+	}
+	notifyCompletion(job)
+}
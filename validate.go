@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	maxBotNameLength   = 60
+	maxGuestNameLength = 100
+	maxPasscodeLength  = 32
+	maxJoinEarly       = time.Hour
+	maxRequestBody     = 64 << 10
+)
+
+// FieldError describes a problem with a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError collects every field problem found in a request
+type ValidationError struct {
+	Fields []FieldError `json:"fields"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		messages[i] = f.Field + ": " + f.Message
+	}
+	return "invalid request: " + strings.Join(messages, "; ")
+}
+
+// add records a problem with a field
+func (e *ValidationError) add(field, format string, args ...any) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// errOrNil returns the error if any field failed validation
+func (e *ValidationError) errOrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Validate checks the request and normalizes the meeting URL, bot name and
+// email in place
+func (r *MeetingRequest) Validate() error {
+	verr := &ValidationError{}
+
+	if normalized, err := normalizeMeetingURL(r.MeetingURL); err != nil {
+		verr.add("meeting_url", "%v", err)
+	} else {
+		r.MeetingURL = normalized
+	}
+
+	r.BotName = strings.TrimSpace(r.BotName)
+	if err := validateBotName(r.BotName); err != nil {
+		verr.add("bot_name", "%v", err)
+	}
+
+	r.GuestEmail = strings.TrimSpace(r.GuestEmail)
+	r.GuestName = strings.TrimSpace(r.GuestName)
+	if r.GuestEmail == "" && r.GuestName == "" {
+		verr.add("email", "email or name of the person to follow is required")
+	}
+	if r.GuestEmail != "" {
+		if addr, err := mail.ParseAddress(r.GuestEmail); err != nil || addr.Address != r.GuestEmail {
+			verr.add("email", "must be a plain email address like jane@example.com")
+		} else {
+			r.GuestEmail = strings.ToLower(addr.Address)
+		}
+	}
+	if platform, err := platformFor(r.MeetingURL); err == nil && !platform.ShowsEmails() && r.GuestName == "" && r.GuestEmail != "" {
+		verr.add("name", "is required for %s meetings, which do not show participant emails", platform.Name())
+	}
+	if utf8.RuneCountInString(r.GuestName) > maxGuestNameLength {
+		verr.add("name", "must be at most %d characters", maxGuestNameLength)
+	}
+
+	r.Passcode = strings.TrimSpace(r.Passcode)
+	if utf8.RuneCountInString(r.Passcode) > maxPasscodeLength {
+		verr.add("passcode", "must be at most %d characters", maxPasscodeLength)
+	}
+
+	if r.Locale = strings.TrimSpace(r.Locale); r.Locale != "" {
+		validateLocale(verr, r)
+	}
+
+	if r.CallbackURL != "" && !validCallbackURL(r.CallbackURL) {
+		verr.add("callback_url", "must be an absolute http or https URL")
+	}
+
+	if r.StartAt == nil && r.JoinEarly != 0 {
+		verr.add("join_early", "requires start_at")
+	}
+	validateJoinEarly(verr, r.JoinEarly)
+
+	return verr.errOrNil()
+}
+
+// Validate checks a reschedule request
+func (r *RescheduleRequest) Validate() error {
+	verr := &ValidationError{}
+	if r.StartAt == nil {
+		verr.add("start_at", "is required")
+	}
+	if r.JoinEarly != nil {
+		validateJoinEarly(verr, *r.JoinEarly)
+	}
+	return verr.errOrNil()
+}
+
+// validateLocale checks that the locale is a language tag the selector
+// pack has selectors for, on a platform whose selectors follow the locale
+func validateLocale(verr *ValidationError, r *MeetingRequest) {
+	if !localePattern.MatchString(r.Locale) {
+		verr.add("locale", "must be a language tag like de or es-MX")
+		return
+	}
+	r.Locale = normalizeLocale(r.Locale)
+	if platform, err := platformFor(r.MeetingURL); err == nil {
+		if _, ok := platform.(localizedPlatform); !ok {
+			verr.add("locale", "is not supported for %s meetings", platform.Name())
+			return
+		}
+	}
+	if !uiSelectors.SupportsLocale(r.Locale) {
+		verr.add("locale", "has no selectors, supported languages are %s", strings.Join(uiSelectors.Locales(), ", "))
+	}
+}
+
+func validateJoinEarly(verr *ValidationError, joinEarly Duration) {
+	if joinEarly < 0 || time.Duration(joinEarly) > maxJoinEarly {
+		verr.add("join_early", "must be between 0s and %v", maxJoinEarly)
+	}
+}
+
+// validCallbackURL reports whether a webhook URL is an absolute http(s) URL
+func validCallbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// validateBotName checks the bot's display name. Only characters that
+// sanitizeName either keeps or strips are allowed, so the name always
+// yields a usable PulseAudio sink name.
+func validateBotName(name string) error {
+	if name == "" {
+		return errors.New("is required")
+	}
+	if utf8.RuneCountInString(name) > maxBotNameLength {
+		return fmt.Errorf("must be at most %d characters", maxBotNameLength)
+	}
+
+	hasAlnum := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			hasAlnum = true
+		case r == ' ' || r == '-' || r == '_' || r == '.' || r == '\'':
+		default:
+			return fmt.Errorf("contains unsupported character %q", r)
+		}
+	}
+	if !hasAlnum {
+		return errors.New("must contain a letter or digit")
+	}
+	return nil
+}
+
+// decodeJSON strictly decodes a request body into v, reporting unknown
+// fields and type mismatches as field errors
+func decodeJSON(r *http.Request, v any) error {
+	dec := json.NewDecoder(io.LimitReader(r.Body, maxRequestBody))
+	dec.DisallowUnknownFields()
+
+	err := dec.Decode(v)
+	if err == nil {
+		if dec.More() {
+			return &ValidationError{Fields: []FieldError{{Field: "body", Message: "must contain a single JSON object"}}}
+		}
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &typeErr):
+		return &ValidationError{Fields: []FieldError{{Field: typeErr.Field, Message: "must be of type " + typeErr.Type.String()}}}
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return &ValidationError{Fields: []FieldError{{Field: field, Message: "is not a known field"}}}
+	case errors.Is(err, io.EOF):
+		return &ValidationError{Fields: []FieldError{{Field: "body", Message: "is required"}}}
+	default:
+		return &ValidationError{Fields: []FieldError{{Field: "body", Message: err.Error()}}}
+	}
+}
+
+// ValidationErrorResponse is the body of a 400 response with field errors
+type ValidationErrorResponse struct {
+	Error  string       `json:"error"`
+	Fields []FieldError `json:"fields"`
+}
+
+// writeValidationError sends a 400 response with machine-readable field errors
+func writeValidationError(w http.ResponseWriter, err error) {
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusBadRequest, ValidationErrorResponse{
+		Error:  "validation_failed",
+		Fields: verr.Fields,
+	})
+}
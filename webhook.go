@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// signatureHeader carries "t=<unix seconds>,v1=<hex HMAC-SHA256>" where
+	// the MAC is computed over "<unix seconds>.<request body>"
+	signatureHeader = "X-MeetAI-Signature"
+
+	webhookMaxAttempts  = 6
+	webhookInitialDelay = 2 * time.Second
+	webhookTimeout      = 10 * time.Second
+)
+
+// webhookSecret is the shared key used to sign completion webhooks
+var webhookSecret = os.Getenv("MEETAI_WEBHOOK_SECRET")
+
+var (
+	webhookClient = &http.Client{Timeout: webhookTimeout}
+	// pendingWebhooks tracks deliveries still in progress
+	pendingWebhooks sync.WaitGroup
+)
+
+// DeliveryAttempt records one try at posting a completion webhook
+type DeliveryAttempt struct {
+	Attempt    int       `json:"attempt"`
+	URL        string    `json:"url"`
+	At         time.Time `json:"at"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// WebhookPayload is the JSON body posted to a job's callback URL when it finishes
+type WebhookPayload struct {
+	JobID      string                  `json:"job_id"`
+	Status     JobStatus               `json:"status"`
+	MeetingURL string                  `json:"meeting_url"`
+	Transcript string                  `json:"transcript,omitempty"`
+	Summary    string                  `json:"summary,omitempty"`
+	Error      string                  `json:"error,omitempty"`
+	Artifacts  map[ArtifactKind]string `json:"artifacts,omitempty"`
+	FinishedAt *time.Time              `json:"finished_at,omitempty"`
+}
+
+// notifyCompletion posts the job result to the callback URLs of its creator
+// and subscribers in the background
+func notifyCompletion(job *Job) {
+	for _, callbackURL := range job.CallbackURLs() {
+		pendingWebhooks.Add(1)
+		go func() {
+			defer pendingWebhooks.Done()
+			deliverWebhook(job, callbackURL)
+		}()
+	}
+}
+
+// webhookFlushTimeout is how long shutdown waits for pending webhooks: the
+// longest a delivery can take, with every attempt timing out and the full
+// backoff between them, so that its last retry is not cut off
+func webhookFlushTimeout() time.Duration {
+	timeout := webhookMaxAttempts * webhookTimeout
+	delay := webhookInitialDelay
+	for attempt := 1; attempt < webhookMaxAttempts; attempt++ {
+		timeout += delay
+		delay *= 2
+	}
+	return timeout
+}
+
+// waitForWebhooks blocks until pending deliveries finish or the timeout expires
+func waitForWebhooks(timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		pendingWebhooks.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		slog.Warn("Timed out waiting for webhook deliveries")
+	}
+}
+
+// deliverWebhook posts the completion payload, retrying with exponential
+// backoff on network errors, 429 and 5xx responses
+func deliverWebhook(job *Job, callbackURL string) {
+	body, err := json.Marshal(buildWebhookPayload(job))
+	if err != nil {
+		job.Logger().Error("Error encoding webhook", "error", err)
+		return
+	}
+
+	delay := webhookInitialDelay
+	for attempt := 1; attempt <= webhookMaxAttempts; attempt++ {
+		start := time.Now()
+		status, err := postWebhook(callbackURL, body)
+		record := DeliveryAttempt{
+			Attempt:    attempt,
+			URL:        callbackURL,
+			At:         start,
+			StatusCode: status,
+			DurationMs: time.Since(start).Milliseconds(),
+		}
+		if err != nil {
+			record.Error = err.Error()
+		}
+		job.AddDelivery(record)
+
+		if err == nil {
+			job.Logger().Info("Delivered webhook", "callback_url", callbackURL)
+			return
+		}
+		if status != 0 && status != http.StatusTooManyRequests && status < 500 {
+			job.Logger().Warn("Webhook rejected, giving up", "callback_url", callbackURL, "status", status)
+			return
+		}
+		if attempt < webhookMaxAttempts {
+			time.Sleep(delay)
+			delay *= 2
+		}
+	}
+	job.Logger().Warn("Giving up on webhook", "callback_url", callbackURL, "attempts", webhookMaxAttempts)
+}
+
+// postWebhook sends one signed webhook request and returns the response status
+func postWebhook(callbackURL string, body []byte) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if webhookSecret != "" {
+		req.Header.Set(signatureHeader, signWebhook(webhookSecret, time.Now(), body))
+	}
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// signWebhook builds the signature header value for a webhook body
+func signWebhook(secret string, at time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(at.Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+// buildWebhookPayload collects the job result, reading the transcript and
+// summary back from disk
+func buildWebhookPayload(job *Job) WebhookPayload {
+	info := job.Info()
+	payload := WebhookPayload{
+		JobID:      info.ID,
+		Status:     info.Status,
+		MeetingURL: info.Request.MeetingURL,
+		Error:      info.Error,
+		Artifacts:  artifactLinks(info),
+		FinishedAt: info.FinishedAt,
+	}
+	if path, ok := info.Artifacts[ArtifactTranscript]; ok {
+		if data, err := os.ReadFile(path); err == nil {
+			payload.Transcript = string(data)
+		}
+	}
+	if path, ok := info.Artifacts[ArtifactSummary]; ok {
+		if data, err := os.ReadFile(path); err == nil {
+			payload.Summary = string(data)
+		}
+	}
+	return payload
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/playwright-community/playwright-go"
+)
+
+const (
+	// zoomLoadTimeout is how long the web client may take to show the join form
+	zoomLoadTimeout = 30 * time.Second
+	// zoomJoinTimeout is how long Join waits for the meeting or waiting
+	// room after submitting the join form
+	zoomJoinTimeout = 45 * time.Second
+	// zoomAudioTimeout is how long JoinAudio waits for the audio prompt
+	zoomAudioTimeout = 30 * time.Second
+)
+
+// zoomMeetingPattern matches the meeting ID in the paths Zoom uses for
+// join links: /j/123, /wc/join/123, /wc/123/join and /s/123
+var zoomMeetingPattern = regexp.MustCompile(`^/(?:j|s|wc/join|wc)/([0-9]{9,11})(?:/join)?$`)
+
+// zoomMeeting drives the Zoom web client
+type zoomMeeting struct{}
+
+func (zoomMeeting) Name() string {
+	return "Zoom"
+}
+
+// Matches accepts zoom.us and its vanity and regional subdomains such as
+// us02web.zoom.us
+func (zoomMeeting) Matches(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	return host == "zoom.us" || strings.HasSuffix(host, ".zoom.us")
+}
+
+// NormalizeURL rewrites any join link to the web client URL,
+// https://host/wc/join/123456789, so the browser skips the launcher page.
+// The embedded passcode in pwd is kept as the web client fills it in.
+func (zoomMeeting) NormalizeURL(u *url.URL) (string, error) {
+	match := zoomMeetingPattern.FindStringSubmatch(strings.TrimRight(u.Path, "/"))
+	if match == nil {
+		return "", errors.New("must be a Zoom join link like https://zoom.us/j/123456789")
+	}
+	normalized := "https://" + strings.ToLower(u.Hostname()) + "/wc/join/" + match[1]
+	if pwd := u.Query().Get("pwd"); pwd != "" {
+		normalized += "?" + url.Values{"pwd": {pwd}}.Encode()
+	}
+	return normalized, nil
+}
+
+// ShowsEmails is false because the participant list only shows display names
+func (zoomMeeting) ShowsEmails() bool {
+	return false
+}
+
+var (
+	zoomInMeeting = []string{
+		"button.footer__leave-btn",
+		"button[aria-label='Leave']",
+	}
+	zoomInWaitingRoom = []string{
+		"text=/host will let you in soon/i",
+		"text=/waiting for the host to start/i",
+		".wr-information",
+	}
+	zoomNameInput = []string{
+		"input#input-for-name",
+		"input#inputname",
+	}
+	zoomPasscodeInput = []string{
+		"input#input-for-pwd",
+		"input#inputpasscode",
+	}
+)
+
+// Join fills in the display name and, when the link does not carry one,
+// the passcode, then joins with camera and microphone off. Meetings with a
+// waiting room, or whose host has not started them yet, leave the bot in
+// the lobby.
+func (zoomMeeting) Join(logger *slog.Logger, page playwright.Page, botName, passcode string) (bool, error) {
+	clickFirstVisible(logger, page, "Accept cookies", "button#onetrust-accept-btn-handler")
+
+	selector, ok := waitForVisible(page, zoomLoadTimeout, zoomNameInput...)
+	if !ok {
+		return false, errors.New("the Zoom join form did not load")
+	}
+	if err := page.Locator(selector).First().Fill(botName); err != nil {
+		return false, errors.New("could not enter the bot name: " + err.Error())
+	}
+	logger.Info("Entered guest name")
+
+	if selector, ok := firstVisible(page, zoomPasscodeInput...); ok {
+		if passcode == "" {
+			return false, errors.New("the meeting requires a passcode; pass it in the link or the passcode field")
+		}
+		if err := page.Locator(selector).First().Fill(passcode); err != nil {
+			return false, errors.New("could not enter the passcode: " + err.Error())
+		}
+		logger.Info("Entered passcode")
+	}
+
+	// The preview screen starts with the fake camera and microphone on
+	clickFirstVisible(logger, page, "Mute", "button#preview-audio-control-button[aria-label='Mute']")
+	clickFirstVisible(logger, page, "Stop Video", "button#preview-video-control-button[aria-label='Stop Video']")
+
+	if !clickFirstVisible(logger, page, "Join",
+		"button.preview-join-button",
+		"button#joinBtn",
+		"button:has-text('Join')",
+	) {
+		return false, errors.New("could not find the join button")
+	}
+
+	// The waiting room has a Leave button too, so it is checked first
+	deadline := time.Now().Add(zoomJoinTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := firstVisible(page, zoomInWaitingRoom...); ok {
+			logger.Info("Waiting to be let into the meeting")
+			return true, nil
+		}
+		if _, ok := firstVisible(page, zoomInMeeting...); ok {
+			return false, nil
+		}
+		if _, ok := firstVisible(page, "text=/passcode (is )?(wrong|incorrect)/i"); ok {
+			return false, errors.New("the passcode was rejected")
+		}
+		time.Sleep(time.Second)
+	}
+	return false, errors.New("the meeting did not load after joining")
+}
+
+// JoinAudio connects the web client to the meeting audio. Until it does,
+// Zoom plays nothing and the recording would be silent.
+func (zoomMeeting) JoinAudio(logger *slog.Logger, page playwright.Page) error {
+	connected := []string{
+		"button[aria-label*='mute my microphone' i]",
+		"button[aria-label*='unmute my microphone' i]",
+	}
+	deadline := time.Now().Add(zoomAudioTimeout)
+	for time.Now().Before(deadline) {
+		if _, ok := firstVisible(page, connected...); ok {
+			return nil
+		}
+		if clickFirstVisible(logger, page, "Join Audio by Computer",
+			"button.join-audio-by-voip__join-btn",
+			"button:has-text('Join Audio by Computer')",
+		) {
+			return nil
+		}
+		// The prompt is reopened from the footer once dismissed
+		clickFirstVisible(logger, page, "Join Audio", "button.join-audio-container__btn:has-text('Join Audio')")
+		time.Sleep(time.Second)
+	}
+	return errors.New("could not connect to the meeting audio")
+}
+
+// Admission reports whether the bot has been let in or removed from the
+// waiting room. The bot counts as admitted once the waiting room is gone.
+func (zoomMeeting) Admission(page playwright.Page) (admitted, denied bool) {
+	_, waiting := firstVisible(page, zoomInWaitingRoom...)
+	_, inMeeting := firstVisible(page, zoomInMeeting...)
+	admitted = inMeeting && !waiting
+	_, denied = firstVisible(page,
+		"text=/removed you from the waiting room/i",
+		"text=/host has removed you/i",
+	)
+	return admitted, denied
+}
+
+// MeetingEnded reports whether the host ended the meeting or removed the bot
+func (zoomMeeting) MeetingEnded(page playwright.Page) (string, bool) {
+	return firstVisible(page,
+		"text=/This meeting has been ended by (the )?host/i",
+		"text=/host has removed you from the meeting/i",
+		"text=/You have left the meeting/i",
+	)
+}
+
+// IsPersonInMeeting looks for the person's display name in the
+// participants panel. Zoom does not show emails, so the email is only
+// matched if the person used it as their name.
+func (z zoomMeeting) IsPersonInMeeting(logger *slog.Logger, page playwright.Page, email, name string) bool {
+	z.openParticipants(logger, page)
+
+	roster := []string{".participants-item__display-name", ".participants-item-position", ".video-avatar__avatar-footer"}
+	return containsText(page, name, roster...) || containsText(page, email, roster...)
+}
+
+// openParticipants opens the participants panel unless it is already open
+func (zoomMeeting) openParticipants(logger *slog.Logger, page playwright.Page) {
+	if _, open := firstVisible(page, ".participants-section-container", "#participants-ul"); open {
+		return
+	}
+	if clickFirstVisible(logger, page, "Participants", "button[aria-label*='participants list' i]", "button.footer-button__participants-icon") {
+		time.Sleep(time.Second)
+	}
+}
+
+// Leave clicks Leave and confirms. Hosts are offered "End Meeting for
+// All" as well, which the bot never is.
+func (zoomMeeting) Leave(logger *slog.Logger, page playwright.Page) {
+	if !clickFirstVisible(logger, page, "Leave", zoomInMeeting...) {
+		logger.Warn("Could not find leave meeting button, closing page instead")
+		return
+	}
+	leaveByButton(logger, page, "button.leave-meeting-options__btn:has-text('Leave Meeting')", "button:has-text('Leave Meeting')")
+}